@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Protocol versions a client can negotiate with HELLO.
+const (
+	// RESP2 is the protocol every connection starts with
+	RESP2 = resp.Protocol2
+	// RESP3 adds maps, sets, doubles, booleans, nulls and push frames
+	RESP3 = resp.Protocol3
+)
+
+// ErrKilled is the cause given to the blocking command of a client killed with CLIENT KILL
+var ErrKilled = errors.New("ERR client killed")
+
+// nextID is the id given to the next client, ids are never reused
+var nextID atomic.Int64
+
+// Info holds the fields of a client reported by CLIENT LIST.
+type Info struct {
+	// ID uniquely identifies the client for the lifetime of the server
+	ID int64
+	// Addr is the address of the remote end of the connection, empty for internal clients
+	Addr string
+	// LocalAddr is the address of the local end of the connection, empty for internal clients
+	LocalAddr string
+	// UnixSocket is set for clients connected through the unix socket
+	UnixSocket bool
+	// CreatedAt is when the connection was accepted
+	CreatedAt time.Time
+	// Protocol is the RESP version negotiated with HELLO
+	Protocol int
+	// Name is the name set with HELLO SETNAME or CLIENT SETNAME, empty if none was set
+	Name string
+	// Authenticated is set once the client is allowed to run commands
+	Authenticated bool
+	// Monitor is set once the client ran MONITOR, the connection then streams the executed commands
+	Monitor bool
+	// Blocked is set while the client waits in a blocking command such as BLPOP
+	Blocked bool
+	// LastInteraction is when the client last sent a command or was woken up from a blocking one
+	LastInteraction time.Time
+	// LastCommand is the name of the last command run by the client
+	LastCommand string
+	// QueryBuffer is the number of bytes received but not processed yet
+	QueryBuffer int
+	// OutputBuffer is the number of bytes of replies not sent yet
+	OutputBuffer int
+	// CloseAfterReply is set to close the connection once the pending replies are written
+	CloseAfterReply bool
+}
+
+// Client holds the state of a single connection.
+// The connection serving the client owns it: it may read every field without locking,
+// but holds the client lock while changing the fields that other connections inspect
+// through Snapshot, e.g. for CLIENT LIST.
+type Client struct {
+	Info
+
+	// mutex protects the fields of Info from concurrent reads by other connections
+	mutex sync.Mutex
+	// closer closes the connection of the client, see Kill
+	closer func() error
+	// unblock cancels the context of the blocking command the client waits in, nil when not blocked
+	unblock context.CancelCauseFunc
+	// killed is set once Kill was called
+	killed atomic.Bool
+	// done is closed once Kill was called, see Done
+	done chan struct{}
+}
+
+// New creates a client speaking RESP2 with the next available id.
+func New() *Client {
+	return &Client{
+		Info: Info{
+			ID:       nextID.Add(1),
+			Protocol: RESP2,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// Lock acquires the client lock, it must be held while changing the fields of the client.
+func (c *Client) Lock() {
+	c.mutex.Lock()
+}
+
+// Unlock releases the client lock.
+func (c *Client) Unlock() {
+	c.mutex.Unlock()
+}
+
+// Snapshot returns a copy of the fields of the client, safe to use from any goroutine.
+func (c *Client) Snapshot() Info {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.Info
+}
+
+// SetCloser registers fn to close the connection of the client when it is killed.
+func (c *Client) SetCloser(fn func() error) {
+	c.closer = fn
+}
+
+// Kill closes the connection of the client, e.g. for CLIENT KILL.
+// It may be called from any goroutine and several times.
+func (c *Client) Kill() {
+	if c.killed.Swap(true) {
+		return
+	}
+	close(c.done)
+	if c.closer == nil {
+		return
+	}
+	_ = c.closer()
+	c.Unblock(ErrKilled)
+}
+
+// BeginBlocking marks the client as blocked and returns the context the blocking command
+// waits with. The context is cancelled by Unblock, with the cause the command should reply with.
+func (c *Client) BeginBlocking() context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Blocked = true
+	c.unblock = cancel
+	return ctx
+}
+
+// EndBlocking marks the client as no longer blocked once its blocking command returned,
+// whether or not the command had to wait. now becomes its last interaction, so the client
+// isn't taken for idle while its reply is being sent.
+func (c *Client) EndBlocking(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Blocked = false
+	c.LastInteraction = now
+	if c.unblock != nil {
+		c.unblock(nil)
+		c.unblock = nil
+	}
+}
+
+// Unblock interrupts the blocking command the client waits in, e.g. for CLIENT UNBLOCK.
+// context.DeadlineExceeded as cause makes the command reply as if its timeout expired,
+// any other cause is replied as an error. Unblock reports whether the client was blocked.
+func (c *Client) Unblock(cause error) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.unblock == nil {
+		return false
+	}
+	c.unblock(cause)
+	c.unblock = nil
+	return true
+}
+
+// Killed reports whether Kill was called.
+func (c *Client) Killed() bool {
+	return c.killed.Load()
+}
+
+// Done returns a channel closed once Kill was called, e.g. to stop waiting on behalf of the client.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// ValidName reports whether name can be used as a client name.
+// Names may not contain spaces, newlines or other special characters so they
+// can be listed unambiguously.
+func ValidName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if name[i] < '!' || name[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package client
+
+import "testing"
+
+func TestNewAssignsIncreasingIDs(t *testing.T) {
+	first := New()
+	second := New()
+	if second.ID <= first.ID {
+		t.Errorf("Expected increasing ids, got %d then %d", first.ID, second.ID)
+	}
+	if first.Protocol != RESP2 {
+		t.Errorf("Expected new clients to speak RESP2, got %d", first.Protocol)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"worker-1", true},
+		{"", true},
+		{"two words", false},
+		{"line\nbreak", false},
+		{"tab\t", false},
+		{"caf\xc3\xa9", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidName(tt.name); got != tt.valid {
+			t.Errorf("ValidName(%q) = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
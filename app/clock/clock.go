@@ -0,0 +1,193 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time and creates timers.
+// Time-dependent code reads the time through a Clock so tests can control it.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer creates a Timer that fires once d has elapsed on this clock
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer delivers the current time on its channel once its duration has elapsed.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. Returns false if it already fired or was stopped.
+	Stop() bool
+}
+
+// Jumper is implemented by clocks whose time can be moved, e.g. by DEBUG JUMP-TIME.
+type Jumper interface {
+	// Jump moves the clock by d, which may be negative
+	Jump(d time.Duration)
+}
+
+// Real is the Clock backed by the system time.
+type Real struct{}
+
+// Now returns the current system time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a timer backed by time.Timer.
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	// timer is the underlying system timer
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// Offset is a Clock that runs at the pace of another clock, shifted by an adjustable offset.
+// It lets a running server move its notion of time without touching the system clock.
+type Offset struct {
+	// base is the clock being shifted
+	base Clock
+	// offset is added to the time of base
+	offset time.Duration
+	// mutex protects access to offset
+	mutex sync.Mutex
+}
+
+// NewOffset creates a new Offset clock following base with no offset.
+func NewOffset(base Clock) *Offset {
+	return &Offset{
+		base: base,
+	}
+}
+
+// Now returns the time of the base clock shifted by the offset.
+func (o *Offset) Now() time.Time {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.base.Now().Add(o.offset)
+}
+
+// NewTimer creates a timer on the base clock, durations are not affected by the offset.
+func (o *Offset) NewTimer(d time.Duration) Timer {
+	return o.base.NewTimer(d)
+}
+
+// Jump adds d to the offset.
+func (o *Offset) Jump(d time.Duration) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.offset += d
+}
+
+// Manual is a Clock that only moves when told to.
+// Its timers fire when the clock is advanced past their deadline.
+type Manual struct {
+	// now is the time currently reported by the clock
+	now time.Time
+	// timers holds the timers that have not fired nor been stopped yet
+	timers []*manualTimer
+	// mutex protects access to now and timers
+	mutex sync.Mutex
+}
+
+// NewManual creates a new Manual clock set to the given time.
+func NewManual(now time.Time) *Manual {
+	return &Manual{
+		now: now,
+	}
+}
+
+// Now returns the time the clock is currently set to.
+func (m *Manual) Now() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.now
+}
+
+// NewTimer creates a timer firing once the clock has been advanced by d.
+func (m *Manual) NewTimer(d time.Duration) Timer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t := &manualTimer{
+		clock:    m,
+		deadline: m.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		t.c <- m.now
+		return t
+	}
+
+	m.timers = append(m.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires the timers whose deadline has passed.
+func (m *Manual) Advance(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.now = m.now.Add(d)
+
+	pending := m.timers[:0]
+	for _, t := range m.timers {
+		if t.deadline.After(m.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- m.now
+	}
+	m.timers = pending
+}
+
+// Jump moves the clock by d like Advance. Moving backwards never fires timers.
+func (m *Manual) Jump(d time.Duration) {
+	m.Advance(d)
+}
+
+// Pending returns the number of timers waiting to fire.
+// Tests use it to wait until a blocking call has armed its timer.
+func (m *Manual) Pending() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.timers)
+}
+
+type manualTimer struct {
+	// clock is the Manual clock the timer belongs to
+	clock *Manual
+	// deadline is the time at which the timer fires
+	deadline time.Time
+	// c receives the time when the timer fires
+	c chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManual(t *testing.T) {
+	start := time.UnixMilli(1000)
+	c := NewManual(start)
+
+	if !c.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, c.Now())
+	}
+
+	c.Advance(1500 * time.Millisecond)
+	if c.Now().UnixMilli() != 2500 {
+		t.Errorf("Expected 2500ms, got %dms", c.Now().UnixMilli())
+	}
+
+	c.Jump(-500 * time.Millisecond)
+	if c.Now().UnixMilli() != 2000 {
+		t.Errorf("Expected 2000ms after jumping back, got %dms", c.Now().UnixMilli())
+	}
+}
+
+func TestManualTimer(t *testing.T) {
+	c := NewManual(time.UnixMilli(0))
+	timer := c.NewTimer(time.Second)
+
+	if c.Pending() != 1 {
+		t.Fatalf("Expected 1 pending timer, got %d", c.Pending())
+	}
+
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("Timer fired before its deadline")
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	select {
+	case fired := <-timer.C():
+		if fired.UnixMilli() != 1000 {
+			t.Errorf("Expected timer to fire at 1000ms, got %dms", fired.UnixMilli())
+		}
+	default:
+		t.Fatal("Timer did not fire at its deadline")
+	}
+
+	if timer.Stop() {
+		t.Error("Expected Stop to return false for a fired timer")
+	}
+}
+
+func TestManualTimerStop(t *testing.T) {
+	c := NewManual(time.UnixMilli(0))
+	timer := c.NewTimer(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Expected Stop to return true for a pending timer")
+	}
+
+	c.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("Stopped timer fired")
+	default:
+	}
+}
+
+func TestOffset(t *testing.T) {
+	base := NewManual(time.UnixMilli(1000))
+	c := NewOffset(base)
+
+	c.Jump(time.Minute)
+	if c.Now().UnixMilli() != 61000 {
+		t.Errorf("Expected 61000ms, got %dms", c.Now().UnixMilli())
+	}
+
+	base.Advance(time.Second)
+	if c.Now().UnixMilli() != 62000 {
+		t.Errorf("Expected offset clock to follow its base, got %dms", c.Now().UnixMilli())
+	}
+}
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	now := Real{}.Now()
+	if now.Before(before) {
+		t.Errorf("Expected real clock to be at least %v, got %v", before, now)
+	}
+
+	timer := Real{}.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("Real timer did not fire")
+	}
+}
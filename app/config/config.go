@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config holds the settings of the server.
+// A Config is filled from its defaults, then from a redis.conf style file,
+// then from command-line options, each source overriding the previous one.
+type Config struct {
+	// File is the path of the config file the configuration was loaded from, empty if none
+	File string
+	// Bind lists the addresses the server listens on
+	Bind []string
+	// Port is the TCP port the server listens on
+	Port int
+	// TLSPort is the TCP port the server accepts TLS connections on, 0 disables it
+	TLSPort int
+	// TLSCertFile is the PEM file holding the certificate of the server
+	TLSCertFile string
+	// TLSKeyFile is the PEM file holding the private key of TLSCertFile
+	TLSKeyFile string
+	// TLSCACertFile is the PEM file holding the CA certificates client certificates are verified with
+	TLSCACertFile string
+	// TLSAuthClients selects whether clients must present a certificate: yes, no or optional
+	TLSAuthClients string
+	// UnixSocket is the path of the unix socket the server also listens on, relative to Dir, empty to disable it
+	UnixSocket string
+	// UnixSocketPerm is the permission bits of the unix socket file, 0 to keep the default ones
+	UnixSocketPerm os.FileMode
+	// Databases is the number of logical databases
+	Databases int
+	// Dir is the directory where data files are written, see Path
+	Dir string
+	// DBFilename is the name of the snapshot file inside Dir
+	DBFilename string
+	// Save lists the snapshot points as pairs of seconds and changes
+	Save []string
+	// AppendOnly reports whether the append only file is enabled
+	AppendOnly bool
+	// RequirePass is the password of the default user, empty if clients don't need to authenticate
+	RequirePass string
+	// MaxClients is the maximum number of connected clients
+	MaxClients int
+	// Timeout closes connections idle for this many seconds, 0 disables it
+	Timeout int
+	// TCPKeepAlive is the period in seconds of TCP keepalive probes, 0 disables them
+	TCPKeepAlive int
+	// MaxMemory is the memory limit in bytes, 0 means no limit
+	MaxMemory int64
+	// MaxMemoryPolicy selects how keys are evicted when MaxMemory is reached. Eviction is not
+	// supported, every policy rejects the commands growing the dataset as noeviction does
+	MaxMemoryPolicy string
+	// LogLevel is the minimum level of the messages logged: debug, verbose, notice or warning
+	LogLevel string
+	// LogFile is the file logs are appended to, empty to log to the standard output
+	LogFile string
+	// LogFormat is the format of log records, text or json
+	LogFormat string
+	// SlowlogLogSlowerThan is the execution time in microseconds above which a command is
+	// reported as slow, a negative value disables it
+	SlowlogLogSlowerThan int
+	// SlowlogMaxLen is the number of entries kept by the slow log
+	SlowlogMaxLen int
+	// ShutdownTimeout is the number of seconds a shutdown waits for running commands to finish
+	// before closing their connections
+	ShutdownTimeout int
+	// EnableDebugCommand allows the DEBUG command: yes, no or local for local connections only
+	EnableDebugCommand string
+	// MetricsBind is the address of the Prometheus metrics HTTP listener
+	MetricsBind string
+	// MetricsPort is the port of the Prometheus metrics HTTP listener, 0 disables it
+	MetricsPort int
+}
+
+// Default returns the configuration used when no file or option overrides it.
+func Default() *Config {
+	return &Config{
+		Bind:                 []string{"0.0.0.0"},
+		Port:                 6379,
+		TLSAuthClients:       "yes",
+		Databases:            16,
+		Dir:                  ".",
+		DBFilename:           "dump.rdb",
+		Save:                 []string{"3600", "1", "300", "100", "60", "10000"},
+		MaxClients:           10000,
+		Timeout:              0,
+		TCPKeepAlive:         300,
+		MaxMemory:            0,
+		MaxMemoryPolicy:      "noeviction",
+		LogLevel:             "notice",
+		LogFile:              "",
+		LogFormat:            "text",
+		SlowlogLogSlowerThan: 10000,
+		SlowlogMaxLen:        128,
+		ShutdownTimeout:      10,
+		EnableDebugCommand:   "no",
+		MetricsBind:          "0.0.0.0",
+		MetricsPort:          0,
+	}
+}
+
+// Clone returns a deep copy of the configuration.
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Bind = append([]string(nil), c.Bind...)
+	clone.Save = append([]string(nil), c.Save...)
+	return &clone
+}
+
+// Set applies a directive to the configuration.
+// Example: Set("port", []string{"7000"})
+// Example: Set("bind", []string{"127.0.0.1", "::1"})
+func (c *Config) Set(name string, args []string) error {
+	p, exists := lookupParam(name)
+	if !exists {
+		return fmt.Errorf("unknown directive")
+	}
+	if len(args) == 0 || (!p.multi && len(args) != 1) {
+		return fmt.Errorf("wrong number of arguments")
+	}
+	return p.set(c, args)
+}
+
+// Get returns the value of a directive formatted as in a config file.
+func (c *Config) Get(name string) (string, bool) {
+	p, exists := lookupParam(name)
+	if !exists {
+		return "", false
+	}
+	return strings.Join(p.get(c), " "), true
+}
+
+// Path resolves the path of a data file against Dir, absolute paths are kept as is.
+// The working directory of the process is never changed, so that several servers
+// embedded in the same process can use different directories.
+// Example: Path("redis.sock") -> "/var/lib/redis/redis.sock" with dir /var/lib/redis
+func (c *Config) Path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(c.Dir, name)
+}
+
+// Addrs returns the TCP addresses the server listens on, one per bound address,
+// none if port is 0 as plaintext TCP is disabled then.
+func (c *Config) Addrs() []string {
+	if c.Port == 0 {
+		return nil
+	}
+	addrs := make([]string, 0, len(c.Bind))
+	for _, host := range c.Bind {
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(c.Port)))
+	}
+	return addrs
+}
+
+// TLSAddrs returns the TCP addresses the server accepts TLS connections on, none if TLS is disabled.
+func (c *Config) TLSAddrs() []string {
+	if c.TLSPort == 0 {
+		return nil
+	}
+	addrs := make([]string, 0, len(c.Bind))
+	for _, host := range c.Bind {
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(c.TLSPort)))
+	}
+	return addrs
+}
+
+// MetricsAddr returns the address of the metrics HTTP listener, empty if it is disabled.
+func (c *Config) MetricsAddr() string {
+	if c.MetricsPort == 0 {
+		return ""
+	}
+	return net.JoinHostPort(c.MetricsBind, strconv.Itoa(c.MetricsPort))
+}
+
+// parseMemory parses a memory size such as "100", "1k", "1kb", "5mb" or "2gb".
+// Units without a "b" are powers of 1000, units with a "b" are powers of 1024.
+func parseMemory(s string) (int64, error) {
+	lower := strings.ToLower(s)
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"kb", 1024},
+		{"mb", 1024 * 1024},
+		{"gb", 1024 * 1024 * 1024},
+		{"k", 1000},
+		{"m", 1000 * 1000},
+		{"g", 1000 * 1000 * 1000},
+		{"b", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(lower, unit.suffix) {
+			lower = strings.TrimSuffix(lower, unit.suffix)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(lower, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/multiplier {
+		return 0, fmt.Errorf("argument must be a memory value")
+	}
+	return n * multiplier, nil
+}
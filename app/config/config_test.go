@@ -0,0 +1,133 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		check   func(c *Config) bool
+		wantErr bool
+	}{
+		{name: "port", args: []string{"7000"}, check: func(c *Config) bool { return c.Port == 7000 }},
+		{name: "PORT", args: []string{"7001"}, check: func(c *Config) bool { return c.Port == 7001 }},
+		{name: "port", args: []string{"70000"}, wantErr: true},
+		{name: "port", args: []string{"abc"}, wantErr: true},
+		{name: "port", args: []string{"1", "2"}, wantErr: true},
+		{name: "bind", args: []string{"127.0.0.1", "::1"}, check: func(c *Config) bool {
+			return reflect.DeepEqual(c.Bind, []string{"127.0.0.1", "::1"})
+		}},
+		{name: "bind", args: []string{}, wantErr: true},
+		{name: "dir", args: []string{"/var/lib/redis"}, check: func(c *Config) bool { return c.Dir == "/var/lib/redis" }},
+		{name: "requirepass", args: []string{"secret"}, check: func(c *Config) bool { return c.RequirePass == "secret" }},
+		{name: "maxclients", args: []string{"0"}, wantErr: true},
+		{name: "timeout", args: []string{"300"}, check: func(c *Config) bool { return c.Timeout == 300 }},
+		{name: "maxmemory", args: []string{"100mb"}, check: func(c *Config) bool { return c.MaxMemory == 100*1024*1024 }},
+		{name: "maxmemory", args: []string{"1G"}, check: func(c *Config) bool { return c.MaxMemory == 1000*1000*1000 }},
+		{name: "maxmemory", args: []string{"lots"}, wantErr: true},
+		{name: "maxmemory-policy", args: []string{"ALLKEYS-LRU"}, check: func(c *Config) bool { return c.MaxMemoryPolicy == "allkeys-lru" }},
+		{name: "maxmemory-policy", args: []string{"sometimes"}, wantErr: true},
+		{name: "enable-debug-command", args: []string{"YES"}, check: func(c *Config) bool { return c.EnableDebugCommand == "yes" }},
+		{name: "enable-debug-command", args: []string{"local"}, check: func(c *Config) bool { return c.EnableDebugCommand == "local" }},
+		{name: "enable-debug-command", args: []string{"sometimes"}, wantErr: true},
+		{name: "unixsocketperm", args: []string{"770"}, check: func(c *Config) bool { return c.UnixSocketPerm == 0o770 }},
+		{name: "unixsocketperm", args: []string{"800"}, wantErr: true},
+		{name: "unixsocketperm", args: []string{"1777"}, wantErr: true},
+		{name: "unknown-directive", args: []string{"1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		c := Default()
+		err := c.Set(tt.name, tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q, %q): expected an error", tt.name, tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q, %q): unexpected error: %v", tt.name, tt.args, err)
+			continue
+		}
+		if !tt.check(c) {
+			t.Errorf("Set(%q, %q) was not applied", tt.name, tt.args)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	c := Default()
+	if err := c.Set("bind", []string{"127.0.0.1", "::1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.Set("unixsocketperm", []string{"0700"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]string{
+		"bind":             "127.0.0.1 ::1",
+		"port":             "6379",
+		"maxmemory":        "0",
+		"maxmemory-policy": "noeviction",
+		"unixsocketperm":   "700",
+	}
+	for name, expected := range tests {
+		value, exists := c.Get(name)
+		if !exists || value != expected {
+			t.Errorf("Get(%q) = %q, %v; want %q", name, value, exists, expected)
+		}
+	}
+
+	if _, exists := c.Get("nope"); exists {
+		t.Error("Expected unknown directives to be missing")
+	}
+}
+
+func TestAddrs(t *testing.T) {
+	c := Default()
+	c.Bind = []string{"127.0.0.1", "::1"}
+	c.Port = 7000
+
+	expected := []string{"127.0.0.1:7000", "[::1]:7000"}
+	if addrs := c.Addrs(); !reflect.DeepEqual(addrs, expected) {
+		t.Errorf("Addrs() = %q, want %q", addrs, expected)
+	}
+
+	// Port 0 disables plaintext TCP
+	c.Port = 0
+	if addrs := c.Addrs(); addrs != nil {
+		t.Errorf("Expected no address with port 0, got %q", addrs)
+	}
+}
+
+func TestMetricsAddr(t *testing.T) {
+	c := Default()
+	if addr := c.MetricsAddr(); addr != "" {
+		t.Errorf("Expected metrics to be disabled by default, got %q", addr)
+	}
+
+	if err := c.Set("metrics-port", []string{"9121"}); err != nil {
+		t.Fatal(err)
+	}
+	if addr := c.MetricsAddr(); addr != "0.0.0.0:9121" {
+		t.Errorf("MetricsAddr() = %q, want %q", addr, "0.0.0.0:9121")
+	}
+}
+
+func TestPath(t *testing.T) {
+	c := Default()
+	if path := c.Path("redis.sock"); path != "redis.sock" {
+		t.Errorf("Path() = %q, want %q", path, "redis.sock")
+	}
+
+	c.Dir = "/var/lib/redis"
+	if path := c.Path("redis.sock"); path != "/var/lib/redis/redis.sock" {
+		t.Errorf("Path() = %q, want %q", path, "/var/lib/redis/redis.sock")
+	}
+	if path := c.Path("/tmp/redis.sock"); path != "/tmp/redis.sock" {
+		t.Errorf("Expected absolute paths to be kept, got %q", path)
+	}
+}
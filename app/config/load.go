@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/parser"
+)
+
+// Error reports an invalid directive in a config file or on the command line.
+type Error struct {
+	// Source is the config file path, or "command line"
+	Source string
+	// Line is the line of the directive in the file, 0 for command-line options
+	Line int
+	// Directive is the offending directive as written
+	Directive string
+	// Err describes what is wrong with the directive
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: '%s': %v", e.Source, e.Line, e.Directive, e.Err)
+	}
+	return fmt.Sprintf("%s: '%s': %v", e.Source, e.Directive, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Load builds the configuration from command-line arguments, excluding the program name.
+// Like redis-server, an optional config file path comes first and is followed by
+// options written as "--directive value...", which override the values of the file.
+// Example: Load([]string{"/etc/redis.conf", "--port", "7000", "--bind", "127.0.0.1", "::1"})
+func Load(args []string) (*Config, error) {
+	c := Default()
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		if err := c.LoadFile(args[0]); err != nil {
+			return nil, err
+		}
+		args = args[1:]
+	}
+
+	if err := c.applyOptions(args); err != nil {
+		return nil, err
+	}
+	if err := checkDir(c); err != nil {
+		return nil, fmt.Errorf("can't use dir '%s': %w", c.Dir, err)
+	}
+	return c, nil
+}
+
+// LoadFile applies the directives of a redis.conf style file.
+func (c *Config) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("can't open config file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	if err := c.parse(path, f); err != nil {
+		return err
+	}
+
+	// Remember an absolute path, as Redis does
+	if c.File, err = filepath.Abs(path); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parse applies the directives read from r, one per line.
+// Blank lines and lines starting with '#' are ignored, arguments are split like inline commands
+// so values containing spaces can be quoted.
+func (c *Config) parse(source string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	seen := make(map[string]bool)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fields, err := parser.SplitArgs(line)
+		if err != nil {
+			return &Error{Source: source, Line: lineNumber, Directive: line, Err: err}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Repeated lines of accumulating directives add to the first one
+		name, args := strings.ToLower(fields[0]), fields[1:]
+		if p, exists := lookupParam(name); exists && p.accumulate && seen[name] {
+			args = append(p.get(c), args...)
+		}
+		seen[name] = true
+
+		if err := c.Set(name, args); err != nil {
+			return &Error{Source: source, Line: lineNumber, Directive: line, Err: err}
+		}
+	}
+	return scanner.Err()
+}
+
+// applyOptions applies "--directive value..." command-line options.
+// Every argument up to the next "--" option is a value of the directive.
+func (c *Config) applyOptions(args []string) error {
+	for i := 0; i < len(args); {
+		if !strings.HasPrefix(args[i], "--") {
+			return &Error{Source: "command line", Directive: args[i], Err: fmt.Errorf("options must start with '--'")}
+		}
+		name := strings.TrimPrefix(args[i], "--")
+
+		end := i + 1
+		for end < len(args) && !strings.HasPrefix(args[end], "--") {
+			end++
+		}
+
+		values := args[i+1 : end]
+		if err := c.Set(name, values); err != nil {
+			directive := strings.TrimSpace(name + " " + strings.Join(values, " "))
+			return &Error{Source: "command line", Directive: directive, Err: err}
+		}
+		i = end
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "redis.conf")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	c, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("Load(nil) = %+v, want the defaults", c)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfigFile(t, `# Network
+bind 127.0.0.1 ::1
+port 7000
+
+  # Indented comment
+requirepass "my secret"
+maxmemory 2mb
+timeout 60
+`)
+
+	c, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(c.Bind, []string{"127.0.0.1", "::1"}) {
+		t.Errorf("Bind = %q", c.Bind)
+	}
+	if c.Port != 7000 || c.RequirePass != "my secret" || c.MaxMemory != 2*1024*1024 || c.Timeout != 60 {
+		t.Errorf("Unexpected config: %+v", c)
+	}
+}
+
+func TestCommandLineOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "port 7000\ntimeout 60\n")
+
+	c, err := Load([]string{path, "--port", "7001", "--bind", "10.0.0.1", "10.0.0.2", "--maxclients", "50"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if c.Port != 7001 {
+		t.Errorf("Expected the command line port to win, got %d", c.Port)
+	}
+	if c.Timeout != 60 {
+		t.Errorf("Expected the file timeout to be kept, got %d", c.Timeout)
+	}
+	if !reflect.DeepEqual(c.Bind, []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Errorf("Bind = %q", c.Bind)
+	}
+	if c.MaxClients != 50 {
+		t.Errorf("MaxClients = %d", c.MaxClients)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("Invalid value in file", func(t *testing.T) {
+		path := writeConfigFile(t, "port 7000\n\nport abc\n")
+		_, err := Load([]string{path})
+
+		var configErr *Error
+		if !errors.As(err, &configErr) {
+			t.Fatalf("Expected a config error, got %v", err)
+		}
+		if configErr.Line != 3 || configErr.Directive != "port abc" {
+			t.Errorf("Unexpected error location: %v", configErr)
+		}
+	})
+
+	t.Run("Unknown directive in file", func(t *testing.T) {
+		path := writeConfigFile(t, "no-such-directive yes\n")
+		if _, err := Load([]string{path}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Unbalanced quotes in file", func(t *testing.T) {
+		path := writeConfigFile(t, "requirepass \"oops\n")
+		if _, err := Load([]string{path}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := Load([]string{filepath.Join(t.TempDir(), "missing.conf")}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Missing dir", func(t *testing.T) {
+		_, err := Load([]string{"--dir", filepath.Join(t.TempDir(), "missing")})
+		if err == nil || !strings.Contains(err.Error(), "can't use dir") {
+			t.Errorf("Expected the missing dir to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("Option without value", func(t *testing.T) {
+		_, err := Load([]string{"--port"})
+		if err == nil || !strings.Contains(err.Error(), "command line") {
+			t.Errorf("Expected a command line error, got %v", err)
+		}
+	})
+
+	t.Run("Value without option", func(t *testing.T) {
+		if _, err := Load([]string{"--port", "7000", "--", "x"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestLoadFileAccumulatesSaveLines(t *testing.T) {
+	path := writeConfigFile(t, "save 900 1\nsave 300 10\n")
+
+	c, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c.Save, []string{"900", "1", "300", "10"}) {
+		t.Errorf("Save = %q", c.Save)
+	}
+	if !filepath.IsAbs(c.File) {
+		t.Errorf("Expected an absolute config file path, got %q", c.File)
+	}
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// param describes a configuration directive.
+type param struct {
+	// name is the lowercase name of the directive
+	name string
+	// multi is set for directives taking several arguments
+	multi bool
+	// accumulate is set for directives whose repeated lines in a config file add up
+	// instead of replacing each other, like "save"
+	accumulate bool
+	// immutable is set for directives that can only be set at startup
+	immutable bool
+	// set validates the arguments of the directive and stores them in the configuration
+	set func(c *Config, args []string) error
+	// get formats the current value of the directive as its list of arguments
+	get func(c *Config) []string
+	// apply performs the side effects of changing the directive at runtime, may be nil
+	apply func(c *Config) error
+}
+
+// params lists every supported directive.
+var params = []*param{
+	immutable(&param{
+		name:  "bind",
+		multi: true,
+		set: func(c *Config, args []string) error {
+			c.Bind = append([]string(nil), args...)
+			return nil
+		},
+		get: func(c *Config) []string { return c.Bind },
+	}),
+	immutable(intParam("port", 0, 65535, func(c *Config) *int { return &c.Port })),
+	immutable(intParam("tls-port", 0, 65535, func(c *Config) *int { return &c.TLSPort })),
+	withApply(stringParam("tls-cert-file", func(c *Config) *string { return &c.TLSCertFile }), checkTLS),
+	withApply(stringParam("tls-key-file", func(c *Config) *string { return &c.TLSKeyFile }), checkTLS),
+	withApply(stringParam("tls-ca-cert-file", func(c *Config) *string { return &c.TLSCACertFile }), checkTLS),
+	withApply(enumParam("tls-auth-clients", []string{"yes", "no", "optional"}, func(c *Config) *string { return &c.TLSAuthClients }), checkTLS),
+	immutable(stringParam("unixsocket", func(c *Config) *string { return &c.UnixSocket })),
+	immutable(&param{
+		name: "unixsocketperm",
+		set: func(c *Config, args []string) error {
+			perm, err := strconv.ParseUint(args[0], 8, 32)
+			if err != nil || perm > 0o777 {
+				return fmt.Errorf("argument must be an octal number between 0 and 777")
+			}
+			c.UnixSocketPerm = os.FileMode(perm)
+			return nil
+		},
+		get: func(c *Config) []string { return []string{strconv.FormatUint(uint64(c.UnixSocketPerm), 8)} },
+	}),
+	immutable(intParam("databases", 1, 1<<31-1, func(c *Config) *int { return &c.Databases })),
+	withApply(stringParam("dir", func(c *Config) *string { return &c.Dir }), checkDir),
+	stringParam("dbfilename", func(c *Config) *string { return &c.DBFilename }),
+	{
+		name:       "save",
+		multi:      true,
+		accumulate: true,
+		set:        setSave,
+		get:        func(c *Config) []string { return c.Save },
+	},
+	{
+		name: "appendonly",
+		set: func(c *Config, args []string) error {
+			enabled, err := parseBool(args[0])
+			if err != nil {
+				return err
+			}
+			if enabled {
+				return fmt.Errorf("append only file persistence is not supported")
+			}
+			c.AppendOnly = false
+			return nil
+		},
+		get: func(c *Config) []string { return []string{formatBool(c.AppendOnly)} },
+	},
+	stringParam("requirepass", func(c *Config) *string { return &c.RequirePass }),
+	intParam("maxclients", 1, 1<<31-1, func(c *Config) *int { return &c.MaxClients }),
+	intParam("timeout", 0, 1<<31-1, func(c *Config) *int { return &c.Timeout }),
+	intParam("tcp-keepalive", 0, 1<<31-1, func(c *Config) *int { return &c.TCPKeepAlive }),
+	memoryParam("maxmemory", func(c *Config) *int64 { return &c.MaxMemory }),
+	enumParam("maxmemory-policy", []string{
+		"volatile-lru", "volatile-lfu", "volatile-random", "volatile-ttl",
+		"allkeys-lru", "allkeys-lfu", "allkeys-random", "noeviction",
+	}, func(c *Config) *string { return &c.MaxMemoryPolicy }),
+	enumParam("loglevel", []string{"debug", "verbose", "notice", "warning"}, func(c *Config) *string { return &c.LogLevel }),
+	immutable(stringParam("logfile", func(c *Config) *string { return &c.LogFile })),
+	immutable(enumParam("log-format", []string{"text", "json"}, func(c *Config) *string { return &c.LogFormat })),
+	intParam("slowlog-log-slower-than", -1, 1<<31-1, func(c *Config) *int { return &c.SlowlogLogSlowerThan }),
+	intParam("slowlog-max-len", 0, 1<<31-1, func(c *Config) *int { return &c.SlowlogMaxLen }),
+	intParam("shutdown-timeout", 0, 1<<31-1, func(c *Config) *int { return &c.ShutdownTimeout }),
+	immutable(enumParam("enable-debug-command", []string{"yes", "no", "local"}, func(c *Config) *string { return &c.EnableDebugCommand })),
+	immutable(stringParam("metrics-bind", func(c *Config) *string { return &c.MetricsBind })),
+	immutable(intParam("metrics-port", 0, 65535, func(c *Config) *int { return &c.MetricsPort })),
+}
+
+// checkDir rejects a dir that isn't an existing directory.
+func checkDir(c *Config) error {
+	info, err := os.Stat(c.Dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", c.Dir)
+	}
+	return nil
+}
+
+// lookupParam returns the directive with the given name, case insensitively.
+func lookupParam(name string) (*param, bool) {
+	name = strings.ToLower(name)
+	for _, p := range params {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// immutable marks p as settable only at startup.
+func immutable(p *param) *param {
+	p.immutable = true
+	return p
+}
+
+// withApply attaches the side effects of changing p at runtime.
+func withApply(p *param, apply func(c *Config) error) *param {
+	p.apply = apply
+	return p
+}
+
+// intParam creates a directive holding an integer between min and max.
+func intParam(name string, min, max int, field func(c *Config) *int) *param {
+	return &param{
+		name: name,
+		set: func(c *Config, args []string) error {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("argument couldn't be parsed into an integer")
+			}
+			if n < min || n > max {
+				return fmt.Errorf("argument must be between %d and %d inclusive", min, max)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) []string { return []string{strconv.Itoa(*field(c))} },
+	}
+}
+
+// stringParam creates a directive holding a free form string.
+func stringParam(name string, field func(c *Config) *string) *param {
+	return &param{
+		name: name,
+		set: func(c *Config, args []string) error {
+			*field(c) = args[0]
+			return nil
+		},
+		get: func(c *Config) []string { return []string{*field(c)} },
+	}
+}
+
+// memoryParam creates a directive holding a number of bytes, accepting units such as "mb".
+func memoryParam(name string, field func(c *Config) *int64) *param {
+	return &param{
+		name: name,
+		set: func(c *Config, args []string) error {
+			n, err := parseMemory(args[0])
+			if err != nil {
+				return err
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) []string { return []string{strconv.FormatInt(*field(c), 10)} },
+	}
+}
+
+// enumParam creates a directive holding one of a fixed set of lowercase values.
+func enumParam(name string, values []string, field func(c *Config) *string) *param {
+	return &param{
+		name: name,
+		set: func(c *Config, args []string) error {
+			value := strings.ToLower(args[0])
+			for _, v := range values {
+				if v == value {
+					*field(c) = value
+					return nil
+				}
+			}
+			return fmt.Errorf("argument(s) must be one of the following: %s", strings.Join(values, ", "))
+		},
+		get: func(c *Config) []string { return []string{*field(c)} },
+	}
+}
+
+// setSave validates "save <seconds> <changes> [<seconds> <changes> ...]".
+// A single empty argument disables snapshotting.
+func setSave(c *Config, args []string) error {
+	if len(args) == 1 && args[0] == "" {
+		c.Save = nil
+		return nil
+	}
+	if len(args)%2 != 0 {
+		return fmt.Errorf("invalid save parameters")
+	}
+	for i := 0; i < len(args); i += 2 {
+		seconds, err := strconv.Atoi(args[i])
+		if err != nil || seconds < 1 {
+			return fmt.Errorf("invalid save parameters")
+		}
+		// Zero changes snapshots every interval even when nothing changed, as in Redis
+		if changes, err := strconv.Atoi(args[i+1]); err != nil || changes < 0 {
+			return fmt.Errorf("invalid save parameters")
+		}
+	}
+	c.Save = append([]string(nil), args...)
+	return nil
+}
+
+// parseBool parses a yes/no directive value.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("argument must be 'yes' or 'no'")
+}
+
+// formatBool formats a boolean as a yes/no directive value.
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
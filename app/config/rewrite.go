@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/parser"
+)
+
+// ErrNoConfigFile is returned by Rewrite when the server was started without a config file.
+var ErrNoConfigFile = errors.New("The server is running without a config file")
+
+// rewriteMarker introduces the directives appended by Rewrite
+const rewriteMarker = "# Generated by CONFIG REWRITE"
+
+// Rewrite writes the configuration in effect back to the file it was loaded from.
+// Comments, blank lines and unknown directives are preserved. Each known directive is
+// updated in place, repeated lines of a directive are merged into the first one, and
+// directives missing from the file are appended when they differ from their default.
+func (s *Store) Rewrite() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.Current()
+	if c.File == "" {
+		return ErrNoConfigFile
+	}
+
+	content, err := os.ReadFile(c.File)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+
+	written := make(map[string]bool)
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		p := directiveOf(line)
+		if p == nil {
+			out = append(out, line)
+			continue
+		}
+		if written[p.name] {
+			continue
+		}
+		written[p.name] = true
+		out = append(out, formatDirective(p, c))
+	}
+
+	defaults := Default()
+	marked := slices.Contains(out, rewriteMarker)
+	for _, p := range params {
+		if written[p.name] || slices.Equal(p.get(c), p.get(defaults)) {
+			continue
+		}
+		if !marked {
+			out = append(out, rewriteMarker)
+			marked = true
+		}
+		out = append(out, formatDirective(p, c))
+	}
+
+	return writeFileAtomically(c.File, []byte(strings.Join(out, "\n")+"\n"))
+}
+
+// directiveOf returns the known directive set by a config file line, nil for comments,
+// blank lines and anything else that must be kept as is.
+func directiveOf(line string) *param {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || trimmed[0] == '#' {
+		return nil
+	}
+	fields, err := parser.SplitArgs(trimmed)
+	if err != nil || len(fields) == 0 {
+		return nil
+	}
+	p, _ := lookupParam(fields[0])
+	return p
+}
+
+// formatDirective formats the current value of a directive as a config file line.
+func formatDirective(p *param, c *Config) string {
+	args := p.get(c)
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(p.name)
+	for _, arg := range args {
+		sb.WriteByte(' ')
+		sb.WriteString(quote(arg))
+	}
+	return sb.String()
+}
+
+// quote returns arg as it must be written in a config file to be read back unchanged.
+// Arguments that are empty or contain spaces, quotes or special characters are double quoted.
+func quote(arg string) string {
+	plain := arg != ""
+	for i := 0; i < len(arg) && plain; i++ {
+		c := arg[i]
+		plain = c > ' ' && c < 0x7f && c != '"' && c != '\'' && c != '\\'
+	}
+	if plain {
+		return arg
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(arg); i++ {
+		switch c := arg[i]; {
+		case c == '\\' || c == '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c < ' ' || c >= 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// writeFileAtomically replaces the file at path with data, so a crash never leaves
+// a truncated config file behind.
+func writeFileAtomically(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
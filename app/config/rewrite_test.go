@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	path := writeConfigFile(t, `# Server settings
+port 7000
+
+# Keep a snapshot often
+save 900 1
+save 300 10
+
+include /etc/redis/extra.conf
+requirepass old
+`)
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// include is not supported, so the file is described by hand as it would be loaded
+	c := Default()
+	c.File = path
+	c.Port = 7000
+	c.Save = []string{"900", "1", "300", "10"}
+	c.RequirePass = "old"
+
+	s := NewStore(c)
+	if err := s.Set([]string{"requirepass", "new secret", "timeout", "60", "save", "60 1000"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.Rewrite(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `# Server settings
+port 7000
+
+# Keep a snapshot often
+save 60 1000
+
+include /etc/redis/extra.conf
+requirepass "new secret"
+# Generated by CONFIG REWRITE
+timeout 60
+`
+	result, _ := os.ReadFile(path)
+	if string(result) != expected {
+		t.Errorf("Rewritten file:\n%s\nwant:\n%s", result, expected)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the file mode to be kept, got %v", info.Mode().Perm())
+	}
+
+	// A second rewrite is stable
+	if err := s.Rewrite(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	again, _ := os.ReadFile(path)
+	if string(again) != expected {
+		t.Errorf("Second rewrite changed the file:\n%s", again)
+	}
+}
+
+func TestRewriteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.conf")
+
+	c := Default()
+	c.File = path
+	s := NewStore(c)
+	if err := s.Set([]string{"requirepass", "a \"quoted\" \\ pass\nword", "save", ""}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.Rewrite(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := Load([]string{path})
+	if err != nil {
+		t.Fatalf("Failed to load the rewritten file: %v", err)
+	}
+	if loaded.RequirePass != s.Current().RequirePass || loaded.Save != nil {
+		t.Errorf("Round trip mismatch: %+v", loaded)
+	}
+}
+
+func TestRewriteWithoutConfigFile(t *testing.T) {
+	s := NewStore(Default())
+	if err := s.Rewrite(); !errors.Is(err, ErrNoConfigFile) {
+		t.Errorf("Expected ErrNoConfigFile, got %v", err)
+	}
+}
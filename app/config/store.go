@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/app/parser"
+)
+
+// SetError reports why CONFIG SET rejected a parameter.
+type SetError struct {
+	// Param is the parameter as given by the client
+	Param string
+	// Unknown is set when the parameter does not exist
+	Unknown bool
+	// Err describes what is wrong with the new value
+	Err error
+}
+
+func (e *SetError) Error() string {
+	if e.Unknown {
+		return fmt.Sprintf("Unknown option or number of arguments for CONFIG SET - '%s'", e.Param)
+	}
+	return fmt.Sprintf("CONFIG SET failed (possibly related to argument '%s') - %v", e.Param, e.Err)
+}
+
+func (e *SetError) Unwrap() error {
+	return e.Err
+}
+
+// Store holds the live configuration of a running server.
+// A published Config is never modified: changes are made to a copy which then replaces it,
+// so the value returned by Current can be read without locking.
+type Store struct {
+	// current is the configuration in effect
+	current atomic.Pointer[Config]
+	// mutex serializes changes
+	mutex sync.Mutex
+	// listeners are notified after every change
+	listeners []func(c *Config)
+}
+
+// NewStore creates a Store publishing c, which must not be modified afterwards.
+func NewStore(c *Config) *Store {
+	s := &Store{}
+	s.current.Store(c)
+	return s
+}
+
+// Current returns the configuration in effect. It must not be modified.
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// OnChange registers fn to be called with the new configuration after every change,
+// so settings held outside of Config can be applied without a restart.
+func (s *Store) OnChange(fn func(c *Config)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Get returns the name and value of every parameter matching one of the glob patterns,
+// alternating names and values. Each parameter is returned once.
+// Example: Get([]string{"max*"}) -> ["maxclients", "10000", "maxmemory", "0", ...]
+func (s *Store) Get(patterns []string) []string {
+	c := s.Current()
+
+	var result []string
+	for _, p := range params {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(strings.ToLower(pattern), p.name); matched {
+				result = append(result, p.name, strings.Join(p.get(c), " "))
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Set changes parameters at runtime, pairs alternating names and values.
+// Either every parameter is changed or, when one of them is rejected, none is.
+// Values of parameters taking several arguments are split on spaces, e.g. "3600 1 300 100".
+func (s *Store) Set(pairs []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	old := s.Current()
+	next := old.Clone()
+
+	var changed []*param
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		name, value := pairs[i], pairs[i+1]
+		p, exists := lookupParam(name)
+		if !exists {
+			return &SetError{Param: name, Unknown: true}
+		}
+		if seen[p.name] {
+			return &SetError{Param: name, Err: fmt.Errorf("duplicate parameter")}
+		}
+		seen[p.name] = true
+		if p.immutable {
+			return &SetError{Param: name, Err: fmt.Errorf("can't set immutable config")}
+		}
+
+		args := []string{value}
+		if p.multi && value != "" {
+			var err error
+			if args, err = parser.SplitArgs(value); err != nil || len(args) == 0 {
+				return &SetError{Param: name, Err: fmt.Errorf("wrong number of arguments")}
+			}
+		}
+		if err := p.set(next, args); err != nil {
+			return &SetError{Param: name, Err: err}
+		}
+		changed = append(changed, p)
+	}
+
+	// Apply the side effects, undoing those already applied if one of them fails
+	for i, p := range changed {
+		if p.apply == nil {
+			continue
+		}
+		if err := p.apply(next); err != nil {
+			for _, applied := range changed[:i] {
+				if applied.apply != nil {
+					_ = applied.apply(old)
+				}
+			}
+			return &SetError{Param: p.name, Err: err}
+		}
+	}
+
+	s.current.Store(next)
+	for _, fn := range s.listeners {
+		fn(next)
+	}
+	return nil
+}
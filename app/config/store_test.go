@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStoreGet(t *testing.T) {
+	s := NewStore(Default())
+
+	tests := []struct {
+		patterns []string
+		expected []string
+	}{
+		{[]string{"port"}, []string{"port", "6379"}},
+		{[]string{"PORT"}, []string{"port", "6379"}},
+		{[]string{"save"}, []string{"save", "3600 1 300 100 60 10000"}},
+		{[]string{"appendonly"}, []string{"appendonly", "no"}},
+		{[]string{"max*"}, []string{"maxclients", "10000", "maxmemory", "0", "maxmemory-policy", "noeviction"}},
+		{[]string{"maxmemory", "max*"}, []string{"maxclients", "10000", "maxmemory", "0", "maxmemory-policy", "noeviction"}},
+		{[]string{"t?meout"}, []string{"timeout", "0"}},
+		{[]string{"nothing*"}, nil},
+	}
+
+	for _, tt := range tests {
+		if result := s.Get(tt.patterns); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Get(%q) = %q, want %q", tt.patterns, result, tt.expected)
+		}
+	}
+
+	all := s.Get([]string{"*"})
+	if len(all) != 2*len(params) {
+		t.Errorf("Expected every parameter to match '*', got %d values", len(all))
+	}
+}
+
+func TestStoreSet(t *testing.T) {
+	s := NewStore(Default())
+	before := s.Current()
+
+	if err := s.Set([]string{"timeout", "300", "maxmemory", "1mb", "save", "900 1 300 10"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c := s.Current()
+	if c.Timeout != 300 || c.MaxMemory != 1024*1024 || !reflect.DeepEqual(c.Save, []string{"900", "1", "300", "10"}) {
+		t.Errorf("Unexpected config after Set: %+v", c)
+	}
+	if before.Timeout != 0 {
+		t.Error("Set must not modify a published configuration")
+	}
+
+	if err := s.Set([]string{"save", "900 0"}); err != nil || !reflect.DeepEqual(s.Current().Save, []string{"900", "0"}) {
+		t.Errorf("Expected a save without changes to be accepted, got %q (%v)", s.Current().Save, err)
+	}
+	if err := s.Set([]string{"save", ""}); err != nil || s.Current().Save != nil {
+		t.Errorf("Expected an empty save to disable snapshots, got %q (%v)", s.Current().Save, err)
+	}
+}
+
+func TestStoreSetErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pairs    []string
+		expected string
+	}{
+		{
+			name:     "Unknown parameter",
+			pairs:    []string{"foo", "bar"},
+			expected: "Unknown option or number of arguments for CONFIG SET - 'foo'",
+		},
+		{
+			name:     "Immutable parameter",
+			pairs:    []string{"port", "7000"},
+			expected: "CONFIG SET failed (possibly related to argument 'port') - can't set immutable config",
+		},
+		{
+			name:     "Invalid value",
+			pairs:    []string{"maxmemory", "lots"},
+			expected: "CONFIG SET failed (possibly related to argument 'maxmemory') - argument must be a memory value",
+		},
+		{
+			name:     "Save interval of zero seconds",
+			pairs:    []string{"save", "0 1"},
+			expected: "CONFIG SET failed (possibly related to argument 'save') - invalid save parameters",
+		},
+		{
+			name:     "Duplicate parameter",
+			pairs:    []string{"timeout", "1", "TIMEOUT", "2"},
+			expected: "CONFIG SET failed (possibly related to argument 'TIMEOUT') - duplicate parameter",
+		},
+		{
+			name:     "Unsupported feature",
+			pairs:    []string{"appendonly", "yes"},
+			expected: "CONFIG SET failed (possibly related to argument 'appendonly') - append only file persistence is not supported",
+		},
+		{
+			name:     "One invalid value rejects every change",
+			pairs:    []string{"timeout", "300", "maxclients", "-1"},
+			expected: "CONFIG SET failed (possibly related to argument 'maxclients') - argument must be between 1 and 2147483647 inclusive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStore(Default())
+			err := s.Set(tt.pairs)
+
+			var setErr *SetError
+			if !errors.As(err, &setErr) || err.Error() != tt.expected {
+				t.Fatalf("Set(%q) = %v, want %q", tt.pairs, err, tt.expected)
+			}
+			if !reflect.DeepEqual(s.Current(), Default()) {
+				t.Errorf("A failed Set must not change the configuration")
+			}
+		})
+	}
+}
+
+func TestStoreOnChange(t *testing.T) {
+	s := NewStore(Default())
+
+	var seen []int
+	s.OnChange(func(c *Config) { seen = append(seen, c.Timeout) })
+
+	_ = s.Set([]string{"timeout", "10"})
+	_ = s.Set([]string{"timeout", "oops"})
+	_ = s.Set([]string{"timeout", "20"})
+
+	if !reflect.DeepEqual(seen, []int{10, 20}) {
+		t.Errorf("Expected listeners to see every applied change, got %v", seen)
+	}
+}
+
+func TestStoreSetDirKeepsWorkingDirectory(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dir := t.TempDir()
+	s := NewStore(Default())
+	if err := s.Set([]string{"dir", dir}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if current, _ := os.Getwd(); current != wd {
+		t.Errorf("Expected the working directory to stay %q, got %q", wd, current)
+	}
+	if path := s.Current().Path("dump.rdb"); path != filepath.Join(dir, "dump.rdb") {
+		t.Errorf("Expected data files to be resolved against dir, got %q", path)
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, invalid := range []string{filepath.Join(dir, "missing"), file} {
+		err = s.Set([]string{"dir", invalid})
+		if err == nil || !strings.Contains(err.Error(), "'dir'") {
+			t.Errorf("Expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+	if s.Current().Dir != dir {
+		t.Errorf("Expected dir to be unchanged, got %q", s.Current().Dir)
+	}
+}
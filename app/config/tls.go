@@ -0,0 +1,59 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig loads the certificates configured by the tls-* directives and returns the
+// settings of the TLS listener. The files are read again on every call, so calling it
+// after they changed on disk picks up the new certificates.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("tls-cert-file and tls-key-file are required")
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the certificate: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch c.TLSAuthClients {
+	case "yes":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	case "optional":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		tlsConfig.ClientAuth = tls.NoClientCert
+	}
+
+	if c.TLSCACertFile != "" {
+		pem, err := os.ReadFile(c.TLSCACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the CA certificates: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to load the CA certificates: no certificate found in %s", c.TLSCACertFile)
+		}
+		tlsConfig.ClientCAs = pool
+	} else if tlsConfig.ClientAuth != tls.NoClientCert {
+		return nil, fmt.Errorf("tls-ca-cert-file is required to verify client certificates")
+	}
+	return tlsConfig, nil
+}
+
+// checkTLS rejects changes to the tls-* directives that leave the TLS listener, if enabled,
+// without valid certificates.
+func checkTLS(c *Config) error {
+	if c.TLSPort == 0 {
+		return nil
+	}
+	_, err := c.TLSConfig()
+	return err
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTLSConfigErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	tests := []struct {
+		name     string
+		cfg      func(c *Config)
+		expected string
+	}{
+		{
+			name:     "No certificate",
+			cfg:      func(c *Config) {},
+			expected: "tls-cert-file and tls-key-file are required",
+		},
+		{
+			name: "Missing certificate file",
+			cfg: func(c *Config) {
+				c.TLSCertFile = missing
+				c.TLSKeyFile = missing
+			},
+			expected: "failed to load the certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		c := Default()
+		tt.cfg(c)
+		if _, err := c.TLSConfig(); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestTLSAddrs(t *testing.T) {
+	c := Default()
+	c.Bind = []string{"127.0.0.1"}
+	if addrs := c.TLSAddrs(); addrs != nil {
+		t.Errorf("Expected TLS to be disabled by default, got %q", addrs)
+	}
+	c.TLSPort = 6380
+	if addrs := c.TLSAddrs(); len(addrs) != 1 || addrs[0] != "127.0.0.1:6380" {
+		t.Errorf("TLSAddrs() = %q, want [127.0.0.1:6380]", addrs)
+	}
+}
+
+func TestStoreSetChecksTLSFiles(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	// Certificates are only loaded when the TLS listener is enabled
+	s := NewStore(Default())
+	if err := s.Set([]string{"tls-cert-file", missing}); err != nil {
+		t.Errorf("Expected the file to be accepted while TLS is disabled, got %v", err)
+	}
+
+	c := Default()
+	c.TLSPort = 6380
+	s = NewStore(c)
+	err := s.Set([]string{"tls-cert-file", missing, "tls-key-file", missing})
+	if err == nil || !strings.Contains(err.Error(), "argument 'tls-cert-file'") {
+		t.Errorf("Expected the missing certificate to be rejected, got %v", err)
+	}
+	if s.Current().TLSCertFile != "" {
+		t.Error("Expected the rejected change not to be applied")
+	}
+}
@@ -0,0 +1,97 @@
+package key_commands
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+	"github.com/codecrafters-io/redis-starter-go/app/stream"
+)
+
+// Copy copies the value stored at source to destination, including its time to live.
+// Returns 1 if the value was copied, 0 if source doesn't exist or destination
+// already exists and REPLACE was not given.
+// Example: COPY source destination [DB destination-db] [REPLACE]
+func (s *Store) Copy(w *resp.Writer, args []string) {
+	if len(args) < 3 {
+		w.WriteError("ERR wrong number of arguments for 'copy' command")
+		return
+	}
+
+	source := args[1]
+	destination := args[2]
+
+	replace := false
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "REPLACE":
+			replace = true
+		case "DB":
+			if i+1 >= len(args) {
+				w.WriteError("ERR syntax error")
+				return
+			}
+			i++
+			db, err := strconv.Atoi(args[i])
+			if err != nil {
+				w.WriteError("ERR value is not an integer or out of range")
+				return
+			}
+			// Only database 0 exists
+			if db != 0 {
+				w.WriteError("ERR DB index is out of range")
+				return
+			}
+		default:
+			w.WriteError("ERR syntax error")
+			return
+		}
+	}
+
+	if source == destination {
+		w.WriteError("ERR source and destination objects are the same")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	item, exists := s.keyspace.Lookup(source)
+	if !exists {
+		w.WriteInteger(0)
+		return
+	}
+
+	if _, exists := s.keyspace.Lookup(destination); exists && !replace {
+		w.WriteInteger(0)
+		return
+	}
+
+	s.keyspace.Set(destination, &keyspace.Item{
+		Type:   item.Type,
+		Value:  cloneValue(item),
+		Expiry: item.Expiry,
+	})
+
+	// Clients blocked on destination may now be served, as if it was pushed to
+	s.keyspace.SignalKeyAsReady(destination)
+
+	w.WriteInteger(1)
+}
+
+// cloneValue returns a deep copy of the value held by item.
+func cloneValue(item *keyspace.Item) any {
+	switch item.Type {
+	case keyspace.TypeList:
+		clone := list.New()
+		clone.PushBackList(item.Value.(*list.List))
+		return clone
+	case keyspace.TypeStream:
+		return item.Value.(*stream.Stream).Clone()
+	default:
+		// Strings are immutable
+		return item.Value
+	}
+}
@@ -0,0 +1,141 @@
+package key_commands_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestCopy(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+		check    []string
+		checkRes string
+	}{
+		{
+			name:     "COPY string key",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"COPY", "a", "b"},
+			expected: ":1\r\n",
+			check:    []string{"GET", "b"},
+			checkRes: "$1\r\nv\r\n",
+		},
+		{
+			name:     "COPY keeps the source",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"COPY", "a", "b"},
+			expected: ":1\r\n",
+			check:    []string{"GET", "a"},
+			checkRes: "$1\r\nv\r\n",
+		},
+		{
+			name:     "COPY missing key",
+			input:    []string{"COPY", "a", "b"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "COPY to an existing key without REPLACE",
+			setup:    [][]string{{"SET", "a", "1"}, {"SET", "b", "2"}},
+			input:    []string{"COPY", "a", "b"},
+			expected: ":0\r\n",
+			check:    []string{"GET", "b"},
+			checkRes: "$1\r\n2\r\n",
+		},
+		{
+			name:     "COPY to an existing key of another type with REPLACE",
+			setup:    [][]string{{"RPUSH", "a", "x"}, {"SET", "b", "2"}},
+			input:    []string{"COPY", "a", "b", "REPLACE"},
+			expected: ":1\r\n",
+			check:    []string{"LRANGE", "b", "0", "-1"},
+			checkRes: "*1\r\n$1\r\nx\r\n",
+		},
+		{
+			name:     "COPY stream key",
+			setup:    [][]string{{"XADD", "a", "1-1", "f", "v"}},
+			input:    []string{"COPY", "a", "b", "DB", "0"},
+			expected: ":1\r\n",
+			check:    []string{"XRANGE", "b", "-", "+"},
+			checkRes: "*1\r\n*2\r\n$3\r\n1-1\r\n*2\r\n$1\r\nf\r\n$1\r\nv\r\n",
+		},
+		{
+			name:     "COPY to the same key",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"COPY", "a", "a"},
+			expected: "-ERR source and destination objects are the same\r\n",
+		},
+		{
+			name:     "COPY to another database",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"COPY", "a", "b", "DB", "1"},
+			expected: "-ERR DB index is out of range\r\n",
+		},
+		{
+			name:     "COPY with unknown option",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"COPY", "a", "b", "NOW"},
+			expected: "-ERR syntax error\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+
+			if tt.check != nil {
+				result = processor.ProcessCommand(tt.check)
+				if result != tt.checkRes {
+					t.Errorf("ProcessCommand(%v) = %q, want %q", tt.check, result, tt.checkRes)
+				}
+			}
+		})
+	}
+}
+
+func TestCopyIsIndependent(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"RPUSH", "a", "x"})
+	processor.ProcessCommand([]string{"COPY", "a", "b"})
+	processor.ProcessCommand([]string{"RPUSH", "b", "y"})
+
+	result := processor.ProcessCommand([]string{"LRANGE", "a", "0", "-1"})
+	expected := "*1\r\n$1\r\nx\r\n"
+	if result != expected {
+		t.Errorf("LRANGE of source after modifying the copy = %q, want %q", result, expected)
+	}
+}
+
+func TestCopyWakesBlockedClients(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"RPUSH", "source", "a"})
+
+	resultChan := make(chan string, 1)
+	go func() {
+		resultChan <- processor.ProcessCommand([]string{"BLPOP", "destination", "0"})
+	}()
+
+	// Give BLPOP time to block
+	time.Sleep(50 * time.Millisecond)
+	processor.ProcessCommand([]string{"COPY", "source", "destination"})
+
+	select {
+	case result := <-resultChan:
+		expected := "*2\r\n$11\r\ndestination\r\n$1\r\na\r\n"
+		if result != expected {
+			t.Errorf("BLPOP result = %q, want %q", result, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP was not woken by COPY")
+	}
+}
@@ -0,0 +1,40 @@
+package key_commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Del removes the specified keys and returns the number of keys that were removed.
+// Example: DEL key1 key2 key3
+func (s *Store) Del(w *resp.Writer, args []string) {
+	s.removeKeys(w, args)
+}
+
+// Unlink removes the specified keys like DEL.
+// Values are reclaimed by the garbage collector, so there is no blocking work to defer.
+// Example: UNLINK key1 key2 key3
+func (s *Store) Unlink(w *resp.Writer, args []string) {
+	s.removeKeys(w, args)
+}
+
+// removeKeys implements DEL and UNLINK.
+func (s *Store) removeKeys(w *resp.Writer, args []string) {
+	if len(args) < 2 {
+		w.WriteError("ERR wrong number of arguments for '" + strings.ToLower(args[0]) + "' command")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	removed := 0
+	for _, key := range args[1:] {
+		if s.keyspace.Delete(key) {
+			removed++
+		}
+	}
+
+	w.WriteInteger(int64(removed))
+}
@@ -0,0 +1,88 @@
+package key_commands_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestDelAndUnlink(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "DEL existing keys of every type",
+			setup:    [][]string{{"SET", "s", "v"}, {"RPUSH", "l", "x"}, {"XADD", "x", "1-1", "f", "v"}},
+			input:    []string{"DEL", "s", "l", "x"},
+			expected: ":3\r\n",
+		},
+		{
+			name:     "DEL counts only existing keys",
+			setup:    [][]string{{"SET", "s", "v"}},
+			input:    []string{"DEL", "s", "missing", "s"},
+			expected: ":1\r\n",
+		},
+		{
+			name:     "UNLINK existing keys",
+			setup:    [][]string{{"SET", "a", "v"}, {"SET", "b", "v"}},
+			input:    []string{"UNLINK", "a", "b", "c"},
+			expected: ":2\r\n",
+		},
+		{
+			name:     "DEL without arguments",
+			input:    []string{"DEL"},
+			expected: "-ERR wrong number of arguments for 'del' command\r\n",
+		},
+		{
+			name:     "UNLINK without arguments",
+			input:    []string{"unlink"},
+			expected: "-ERR wrong number of arguments for 'unlink' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDelExpiredKey(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"SET", "s", "v", "PX", "1"})
+	time.Sleep(5 * time.Millisecond)
+
+	result := processor.ProcessCommand([]string{"DEL", "s"})
+	if result != ":0\r\n" {
+		t.Errorf("DEL of an expired key = %q, want %q", result, ":0\r\n")
+	}
+}
+
+func TestDelRemovesValue(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"RPUSH", "l", "x"})
+	processor.ProcessCommand([]string{"DEL", "l"})
+
+	// The key can now be reused for another type
+	result := processor.ProcessCommand([]string{"SET", "l", "v"})
+	if result != "+OK\r\n" {
+		t.Errorf("SET after DEL = %q, want %q", result, "+OK\r\n")
+	}
+
+	result = processor.ProcessCommand([]string{"TYPE", "l"})
+	if result != "+string\r\n" {
+		t.Errorf("TYPE after DEL and SET = %q, want %q", result, "+string\r\n")
+	}
+}
@@ -0,0 +1,27 @@
+package key_commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Exists returns the number of the specified keys that exist.
+// A key mentioned several times is counted several times.
+// Example: EXISTS key1 key2 key1
+func (s *Store) Exists(w *resp.Writer, args []string) {
+	if len(args) < 2 {
+		w.WriteError("ERR wrong number of arguments for 'exists' command")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	count := 0
+	for _, key := range args[1:] {
+		if _, exists := s.keyspace.LookupRead(key); exists {
+			count++
+		}
+	}
+
+	w.WriteInteger(int64(count))
+}
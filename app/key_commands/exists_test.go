@@ -0,0 +1,64 @@
+package key_commands_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestExistsAndTouch(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "EXISTS missing key",
+			input:    []string{"EXISTS", "missing"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "EXISTS keys of every type",
+			setup:    [][]string{{"SET", "s", "v"}, {"RPUSH", "l", "x"}, {"XADD", "x", "1-1", "f", "v"}},
+			input:    []string{"EXISTS", "s", "l", "x", "missing"},
+			expected: ":3\r\n",
+		},
+		{
+			name:     "EXISTS counts repeated keys every time",
+			setup:    [][]string{{"SET", "s", "v"}},
+			input:    []string{"EXISTS", "s", "s", "s"},
+			expected: ":3\r\n",
+		},
+		{
+			name:     "TOUCH counts existing keys",
+			setup:    [][]string{{"SET", "s", "v"}, {"RPUSH", "l", "x"}},
+			input:    []string{"TOUCH", "s", "l", "missing"},
+			expected: ":2\r\n",
+		},
+		{
+			name:     "EXISTS without arguments",
+			input:    []string{"EXISTS"},
+			expected: "-ERR wrong number of arguments for 'exists' command\r\n",
+		},
+		{
+			name:     "TOUCH without arguments",
+			input:    []string{"TOUCH"},
+			expected: "-ERR wrong number of arguments for 'touch' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
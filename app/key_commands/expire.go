@@ -0,0 +1,129 @@
+package key_commands
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Expire sets a timeout in seconds on key.
+// Example: EXPIRE mykey 10 [NX|XX|GT|LT]
+func (s *Store) Expire(w *resp.Writer, args []string) {
+	s.expire(w, args, 1000, false)
+}
+
+// PExpire sets a timeout in milliseconds on key.
+// Example: PEXPIRE mykey 1500 [NX|XX|GT|LT]
+func (s *Store) PExpire(w *resp.Writer, args []string) {
+	s.expire(w, args, 1, false)
+}
+
+// ExpireAt sets the expiration of key to an absolute unix time in seconds.
+// Example: EXPIREAT mykey 1293840000 [NX|XX|GT|LT]
+func (s *Store) ExpireAt(w *resp.Writer, args []string) {
+	s.expire(w, args, 1000, true)
+}
+
+// PExpireAt sets the expiration of key to an absolute unix time in milliseconds.
+// Example: PEXPIREAT mykey 1555555555005 [NX|XX|GT|LT]
+func (s *Store) PExpireAt(w *resp.Writer, args []string) {
+	s.expire(w, args, 1, true)
+}
+
+// expire implements the EXPIRE family. unit is the number of milliseconds in one unit
+// of the given time, absolute tells whether the time is a unix timestamp or relative to now.
+// Returns 1 if the timeout was set, 0 if the key doesn't exist or a condition was not met.
+func (s *Store) expire(w *resp.Writer, args []string, unit int64, absolute bool) {
+	name := strings.ToLower(args[0])
+	if len(args) < 3 {
+		w.WriteError("ERR wrong number of arguments for '" + name + "' command")
+		return
+	}
+
+	key := args[1]
+	when, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		w.WriteError("ERR value is not an integer or out of range")
+		return
+	}
+
+	var nx, xx, gt, lt bool
+	for _, option := range args[3:] {
+		switch strings.ToUpper(option) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "GT":
+			gt = true
+		case "LT":
+			lt = true
+		default:
+			w.WriteError("ERR Unsupported option " + resp.ErrorArg(option))
+			return
+		}
+	}
+	if nx && (xx || gt || lt) {
+		w.WriteError("ERR NX and XX, GT or LT options at the same time are not compatible")
+		return
+	}
+	if gt && lt {
+		w.WriteError("ERR GT and LT options at the same time are not compatible")
+		return
+	}
+
+	// Convert the given time to an absolute unix time in milliseconds, checking for overflows
+	if when > math.MaxInt64/unit || when < math.MinInt64/unit {
+		w.WriteError("ERR invalid expire time in '" + name + "' command")
+		return
+	}
+	expiry := when * unit
+	now := s.keyspace.Now()
+	if !absolute {
+		if expiry > math.MaxInt64-now {
+			w.WriteError("ERR invalid expire time in '" + name + "' command")
+			return
+		}
+		expiry += now
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	item, exists := s.keyspace.Lookup(key)
+	if !exists {
+		w.WriteInteger(0)
+		return
+	}
+
+	// A key without a timeout is considered to have an infinite time to live
+	hasExpiry := item.Expiry != 0
+	if nx && hasExpiry {
+		w.WriteInteger(0)
+		return
+	}
+	if xx && !hasExpiry {
+		w.WriteInteger(0)
+		return
+	}
+	if gt && (!hasExpiry || expiry <= item.Expiry) {
+		w.WriteInteger(0)
+		return
+	}
+	if lt && hasExpiry && expiry >= item.Expiry {
+		w.WriteInteger(0)
+		return
+	}
+
+	// A timeout in the past deletes the key right away
+	if expiry <= now {
+		s.keyspace.Delete(key)
+		w.WriteInteger(1)
+		return
+	}
+
+	s.keyspace.SetExpiry(key, expiry)
+	w.WriteInteger(1)
+}
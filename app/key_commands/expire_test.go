@@ -0,0 +1,230 @@
+package key_commands_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestExpire(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+		check    []string
+		checkRes string
+	}{
+		{
+			name:     "EXPIRE sets a timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":100\r\n",
+		},
+		{
+			name:     "EXPIRE missing key",
+			input:    []string{"EXPIRE", "k", "100"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "EXPIRE applies to lists",
+			setup:    [][]string{{"RPUSH", "k", "x"}},
+			input:    []string{"PEXPIRE", "k", "100000"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":100\r\n",
+		},
+		{
+			name:     "EXPIRE applies to streams",
+			setup:    [][]string{{"XADD", "k", "1-1", "f", "v"}},
+			input:    []string{"EXPIRE", "k", "100"},
+			expected: ":1\r\n",
+			check:    []string{"PTTL", "k"},
+			checkRes: ":100000\r\n",
+		},
+		{
+			name:     "EXPIRE with a negative timeout deletes the key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "-1"},
+			expected: ":1\r\n",
+			check:    []string{"EXISTS", "k"},
+			checkRes: ":0\r\n",
+		},
+		{
+			name:     "EXPIREAT in the past deletes the key",
+			setup:    [][]string{{"RPUSH", "k", "x"}},
+			input:    []string{"EXPIREAT", "k", "1"},
+			expected: ":1\r\n",
+			check:    []string{"EXISTS", "k"},
+			checkRes: ":0\r\n",
+		},
+		{
+			name:     "EXPIRE NX on a key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "NX"},
+			expected: ":1\r\n",
+		},
+		{
+			name:     "EXPIRE NX on a key with a timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "100", "NX"},
+			expected: ":0\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":50\r\n",
+		},
+		{
+			name:     "EXPIRE XX on a key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "XX"},
+			expected: ":0\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":-1\r\n",
+		},
+		{
+			name:     "EXPIRE XX on a key with a timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "100", "xx"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":100\r\n",
+		},
+		{
+			name:     "EXPIRE GT with a greater timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "100", "GT"},
+			expected: ":1\r\n",
+		},
+		{
+			name:     "EXPIRE GT with a smaller timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "10", "GT"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "EXPIRE GT on a key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "GT"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "EXPIRE LT with a smaller timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "10", "LT"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":10\r\n",
+		},
+		{
+			name:     "EXPIRE LT with a greater timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "50"}},
+			input:    []string{"EXPIRE", "k", "100", "LT"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "EXPIRE LT on a key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "LT"},
+			expected: ":1\r\n",
+		},
+		{
+			name:     "EXPIRE NX and XX together",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "NX", "XX"},
+			expected: "-ERR NX and XX, GT or LT options at the same time are not compatible\r\n",
+		},
+		{
+			name:     "EXPIRE GT and LT together",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "GT", "LT"},
+			expected: "-ERR GT and LT options at the same time are not compatible\r\n",
+		},
+		{
+			name:     "EXPIRE with an unknown option",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "100", "FOO"},
+			expected: "-ERR Unsupported option FOO\r\n",
+		},
+		{
+			name:     "EXPIRE with a non integer timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "abc"},
+			expected: "-ERR value is not an integer or out of range\r\n",
+		},
+		{
+			name:     "EXPIRE with an overflowing timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"EXPIRE", "k", "9223372036854775807"},
+			expected: "-ERR invalid expire time in 'expire' command\r\n",
+		},
+		{
+			name:     "PEXPIREAT with wrong number of arguments",
+			input:    []string{"PEXPIREAT", "k"},
+			expected: "-ERR wrong number of arguments for 'pexpireat' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+
+			if tt.check != nil {
+				result = processor.ProcessCommand(tt.check)
+				if result != tt.checkRes {
+					t.Errorf("ProcessCommand(%v) = %q, want %q", tt.check, result, tt.checkRes)
+				}
+			}
+		})
+	}
+}
+
+func TestExpireAt(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"SET", "k", "v"})
+
+	at := time.Now().Add(time.Hour).UnixMilli()
+	result := processor.ProcessCommand([]string{"PEXPIREAT", "k", strconv.FormatInt(at, 10)})
+	if result != ":1\r\n" {
+		t.Fatalf("PEXPIREAT result = %q, want %q", result, ":1\r\n")
+	}
+
+	result = processor.ProcessCommand([]string{"PEXPIRETIME", "k"})
+	expected := ":" + strconv.FormatInt(at, 10) + "\r\n"
+	if result != expected {
+		t.Errorf("PEXPIRETIME result = %q, want %q", result, expected)
+	}
+
+	result = processor.ProcessCommand([]string{"EXPIRETIME", "k"})
+	expected = ":" + strconv.FormatInt(at/1000, 10) + "\r\n"
+	if result != expected {
+		t.Errorf("EXPIRETIME result = %q, want %q", result, expected)
+	}
+}
+
+func TestExpiredKeyIsGone(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"RPUSH", "k", "x"})
+	processor.ProcessCommand([]string{"PEXPIRE", "k", "1"})
+	time.Sleep(5 * time.Millisecond)
+
+	result := processor.ProcessCommand([]string{"LLEN", "k"})
+	if result != ":0\r\n" {
+		t.Errorf("LLEN of an expired list = %q, want %q", result, ":0\r\n")
+	}
+
+	result = processor.ProcessCommand([]string{"TTL", "k"})
+	if result != ":-2\r\n" {
+		t.Errorf("TTL of an expired list = %q, want %q", result, ":-2\r\n")
+	}
+}
@@ -0,0 +1,27 @@
+package key_commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Persist removes the timeout of key.
+// Returns 1 if the timeout was removed, 0 if the key doesn't exist or has no timeout.
+// Example: PERSIST mykey
+func (s *Store) Persist(w *resp.Writer, args []string) {
+	if len(args) != 2 {
+		w.WriteError("ERR wrong number of arguments for 'persist' command")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	item, exists := s.keyspace.Lookup(args[1])
+	if !exists || item.Expiry == 0 {
+		w.WriteInteger(0)
+		return
+	}
+
+	s.keyspace.SetExpiry(args[1], 0)
+	w.WriteInteger(1)
+}
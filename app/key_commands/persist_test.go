@@ -0,0 +1,72 @@
+package key_commands_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestPersist(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+		check    []string
+		checkRes string
+	}{
+		{
+			name:     "PERSIST key with timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "100"}},
+			input:    []string{"PERSIST", "k"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":-1\r\n",
+		},
+		{
+			name:     "PERSIST list with timeout",
+			setup:    [][]string{{"RPUSH", "k", "x"}, {"EXPIRE", "k", "100"}},
+			input:    []string{"PERSIST", "k"},
+			expected: ":1\r\n",
+			check:    []string{"TTL", "k"},
+			checkRes: ":-1\r\n",
+		},
+		{
+			name:     "PERSIST key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"PERSIST", "k"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "PERSIST missing key",
+			input:    []string{"PERSIST", "k"},
+			expected: ":0\r\n",
+		},
+		{
+			name:     "PERSIST with wrong number of arguments",
+			input:    []string{"PERSIST"},
+			expected: "-ERR wrong number of arguments for 'persist' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+
+			if tt.check != nil {
+				result = processor.ProcessCommand(tt.check)
+				if result != tt.checkRes {
+					t.Errorf("ProcessCommand(%v) = %q, want %q", tt.check, result, tt.checkRes)
+				}
+			}
+		})
+	}
+}
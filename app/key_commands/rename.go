@@ -0,0 +1,72 @@
+package key_commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Rename renames key to newkey, overwriting newkey if it already exists.
+// The value keeps its time to live.
+// Example: RENAME mykey myotherkey
+func (s *Store) Rename(w *resp.Writer, args []string) {
+	if len(args) != 3 {
+		w.WriteError("ERR wrong number of arguments for 'rename' command")
+		return
+	}
+
+	key := args[1]
+	newKey := args[2]
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	if _, exists := s.keyspace.Lookup(key); !exists {
+		w.WriteError("ERR no such key")
+		return
+	}
+
+	if key != newKey {
+		s.rename(key, newKey)
+	}
+
+	w.WriteSimpleString("OK")
+}
+
+// RenameNX renames key to newkey only if newkey does not exist yet.
+// Returns 1 if the key was renamed, 0 if newkey already exists.
+// Example: RENAMENX mykey myotherkey
+func (s *Store) RenameNX(w *resp.Writer, args []string) {
+	if len(args) != 3 {
+		w.WriteError("ERR wrong number of arguments for 'renamenx' command")
+		return
+	}
+
+	key := args[1]
+	newKey := args[2]
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	if _, exists := s.keyspace.Lookup(key); !exists {
+		w.WriteError("ERR no such key")
+		return
+	}
+
+	if _, exists := s.keyspace.Lookup(newKey); exists {
+		w.WriteInteger(0)
+		return
+	}
+
+	s.rename(key, newKey)
+	w.WriteInteger(1)
+}
+
+// rename moves the item stored at key to newKey, replacing any value stored at newKey.
+// The keyspace lock must be held and key must exist.
+func (s *Store) rename(key string, newKey string) {
+	item, _ := s.keyspace.Lookup(key)
+	s.keyspace.Delete(key)
+	s.keyspace.Set(newKey, item)
+
+	// Clients blocked on newKey may now be served, as if it was pushed to
+	s.keyspace.SignalKeyAsReady(newKey)
+}
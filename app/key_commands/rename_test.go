@@ -0,0 +1,134 @@
+package key_commands_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+		check    []string
+		checkRes string
+	}{
+		{
+			name:     "RENAME string key",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"RENAME", "a", "b"},
+			expected: "+OK\r\n",
+			check:    []string{"GET", "b"},
+			checkRes: "$1\r\nv\r\n",
+		},
+		{
+			name:     "RENAME removes the source",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"RENAME", "a", "b"},
+			expected: "+OK\r\n",
+			check:    []string{"EXISTS", "a"},
+			checkRes: ":0\r\n",
+		},
+		{
+			name:     "RENAME overwrites a destination of another type",
+			setup:    [][]string{{"RPUSH", "a", "x", "y"}, {"SET", "b", "v"}},
+			input:    []string{"RENAME", "a", "b"},
+			expected: "+OK\r\n",
+			check:    []string{"LRANGE", "b", "0", "-1"},
+			checkRes: "*2\r\n$1\r\nx\r\n$1\r\ny\r\n",
+		},
+		{
+			name:     "RENAME to the same key",
+			setup:    [][]string{{"SET", "a", "v"}},
+			input:    []string{"RENAME", "a", "a"},
+			expected: "+OK\r\n",
+			check:    []string{"GET", "a"},
+			checkRes: "$1\r\nv\r\n",
+		},
+		{
+			name:     "RENAME missing key",
+			input:    []string{"RENAME", "a", "b"},
+			expected: "-ERR no such key\r\n",
+		},
+		{
+			name:     "RENAMENX to a new key",
+			setup:    [][]string{{"XADD", "a", "1-1", "f", "v"}},
+			input:    []string{"RENAMENX", "a", "b"},
+			expected: ":1\r\n",
+			check:    []string{"TYPE", "b"},
+			checkRes: "+stream\r\n",
+		},
+		{
+			name:     "RENAMENX to an existing key",
+			setup:    [][]string{{"SET", "a", "1"}, {"SET", "b", "2"}},
+			input:    []string{"RENAMENX", "a", "b"},
+			expected: ":0\r\n",
+			check:    []string{"GET", "b"},
+			checkRes: "$1\r\n2\r\n",
+		},
+		{
+			name:     "RENAMENX missing key",
+			input:    []string{"RENAMENX", "a", "b"},
+			expected: "-ERR no such key\r\n",
+		},
+		{
+			name:     "RENAME with wrong number of arguments",
+			input:    []string{"RENAME", "a"},
+			expected: "-ERR wrong number of arguments for 'rename' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+
+			if tt.check != nil {
+				result = processor.ProcessCommand(tt.check)
+				if result != tt.checkRes {
+					t.Errorf("ProcessCommand(%v) = %q, want %q", tt.check, result, tt.checkRes)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameWakesBlockedClients(t *testing.T) {
+	processor := processor.NewProcessor()
+	processor.ProcessCommand([]string{"RPUSH", "source", "a", "b"})
+
+	resultChan := make(chan string, 1)
+	go func() {
+		resultChan <- processor.ProcessCommand([]string{"BLPOP", "destination", "0"})
+	}()
+
+	// Give BLPOP time to block
+	time.Sleep(50 * time.Millisecond)
+	processor.ProcessCommand([]string{"RENAME", "source", "destination"})
+
+	select {
+	case result := <-resultChan:
+		expected := "*2\r\n$11\r\ndestination\r\n$1\r\na\r\n"
+		if result != expected {
+			t.Errorf("BLPOP result = %q, want %q", result, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP was not woken by RENAME")
+	}
+
+	result := processor.ProcessCommand([]string{"LRANGE", "destination", "0", "-1"})
+	expected := "*1\r\n$1\r\nb\r\n"
+	if result != expected {
+		t.Errorf("LRANGE after BLPOP = %q, want %q", result, expected)
+	}
+}
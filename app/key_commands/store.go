@@ -0,0 +1,17 @@
+package key_commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+)
+
+type Store struct {
+	// keyspace holds the values of all keys whatever their type
+	keyspace *keyspace.Keyspace
+}
+
+// NewStore creates a new Store instance backed by the given keyspace.
+func NewStore(ks *keyspace.Keyspace) *Store {
+	return &Store{
+		keyspace: ks,
+	}
+}
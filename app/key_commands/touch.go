@@ -0,0 +1,27 @@
+package key_commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Touch returns the number of the specified keys that exist.
+// Keys carry no access time, so touching a key has no other effect.
+// Example: TOUCH key1 key2
+func (s *Store) Touch(w *resp.Writer, args []string) {
+	if len(args) < 2 {
+		w.WriteError("ERR wrong number of arguments for 'touch' command")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	count := 0
+	for _, key := range args[1:] {
+		if _, exists := s.keyspace.Lookup(key); exists {
+			count++
+		}
+	}
+
+	w.WriteInteger(int64(count))
+}
@@ -0,0 +1,69 @@
+package key_commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// TTL returns the remaining time to live of key in seconds.
+// Returns -2 if the key doesn't exist and -1 if it has no timeout.
+// Example: TTL mykey
+func (s *Store) TTL(w *resp.Writer, args []string) {
+	s.ttl(w, args, 1000, false)
+}
+
+// PTTL returns the remaining time to live of key in milliseconds.
+// Returns -2 if the key doesn't exist and -1 if it has no timeout.
+// Example: PTTL mykey
+func (s *Store) PTTL(w *resp.Writer, args []string) {
+	s.ttl(w, args, 1, false)
+}
+
+// ExpireTime returns the absolute unix time in seconds at which key will expire.
+// Returns -2 if the key doesn't exist and -1 if it has no timeout.
+// Example: EXPIRETIME mykey
+func (s *Store) ExpireTime(w *resp.Writer, args []string) {
+	s.ttl(w, args, 1000, true)
+}
+
+// PExpireTime returns the absolute unix time in milliseconds at which key will expire.
+// Returns -2 if the key doesn't exist and -1 if it has no timeout.
+// Example: PEXPIRETIME mykey
+func (s *Store) PExpireTime(w *resp.Writer, args []string) {
+	s.ttl(w, args, 1, true)
+}
+
+// ttl implements the TTL family. unit is the number of milliseconds in one unit of the
+// reply, absolute tells whether to reply with the unix expiration time or the time left.
+func (s *Store) ttl(w *resp.Writer, args []string, unit int64, absolute bool) {
+	if len(args) != 2 {
+		w.WriteError("ERR wrong number of arguments for '" + strings.ToLower(args[0]) + "' command")
+		return
+	}
+
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	item, exists := s.keyspace.LookupRead(args[1])
+	if !exists {
+		w.WriteInteger(-2)
+		return
+	}
+	if item.Expiry == 0 {
+		w.WriteInteger(-1)
+		return
+	}
+
+	if absolute {
+		w.WriteInteger(item.Expiry / unit)
+		return
+	}
+
+	remaining := item.Expiry - s.keyspace.Now()
+	if remaining < 0 {
+		remaining = 0
+	}
+	// Round to the closest unit like Redis does
+	w.WriteInteger((remaining + unit/2) / unit)
+}
@@ -0,0 +1,75 @@
+package key_commands_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "TTL missing key",
+			input:    []string{"TTL", "k"},
+			expected: ":-2\r\n",
+		},
+		{
+			name:     "TTL key without timeout",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"TTL", "k"},
+			expected: ":-1\r\n",
+		},
+		{
+			name:     "TTL key with timeout",
+			setup:    [][]string{{"SET", "k", "v", "EX", "100"}},
+			input:    []string{"TTL", "k"},
+			expected: ":100\r\n",
+		},
+		{
+			name:     "PTTL missing key",
+			input:    []string{"PTTL", "k"},
+			expected: ":-2\r\n",
+		},
+		{
+			name:     "PTTL key without timeout",
+			setup:    [][]string{{"RPUSH", "k", "x"}},
+			input:    []string{"PTTL", "k"},
+			expected: ":-1\r\n",
+		},
+		{
+			name:     "EXPIRETIME missing key",
+			input:    []string{"EXPIRETIME", "k"},
+			expected: ":-2\r\n",
+		},
+		{
+			name:     "PEXPIRETIME key without timeout",
+			setup:    [][]string{{"XADD", "k", "1-1", "f", "v"}},
+			input:    []string{"PEXPIRETIME", "k"},
+			expected: ":-1\r\n",
+		},
+		{
+			name:     "TTL with wrong number of arguments",
+			input:    []string{"TTL"},
+			expected: "-ERR wrong number of arguments for 'ttl' command\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := processor.NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
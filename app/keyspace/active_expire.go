@@ -0,0 +1,160 @@
+package keyspace
+
+import (
+	"time"
+)
+
+const (
+	// activeExpireKeysPerLoop is the number of keys with a timeout sampled in each loop of a cycle
+	activeExpireKeysPerLoop = 20
+	// activeExpireAcceptableStale is the percentage of expired keys among the sampled ones
+	// under which the cycle stops, as there is little memory left to reclaim
+	activeExpireAcceptableStale = 10
+	// activeExpireTimeLimit is the longest a single cycle may hold the keyspace lock
+	activeExpireTimeLimit = 25 * time.Millisecond
+	// ActiveExpireInterval is how often the active expire cycle runs
+	ActiveExpireInterval = 100 * time.Millisecond
+)
+
+// ExpireStats holds the expiration counters of a keyspace.
+type ExpireStats struct {
+	// ExpiredKeys is the number of keys removed because their timeout elapsed
+	ExpiredKeys int64
+	// ExpiredStalePerc is a running estimate of the percentage of keys with a timeout that
+	// are already expired but not reclaimed yet
+	ExpiredStalePerc float64
+	// AvgTTL is a running estimate in milliseconds of the time to live of keys with a timeout
+	AvgTTL int64
+}
+
+// LookupStats counts the outcome of read lookups, see LookupRead.
+type LookupStats struct {
+	// Hits is the number of lookups that found the key
+	Hits int64
+	// Misses is the number of lookups of a missing or expired key
+	Misses int64
+}
+
+// ExpireStats returns a snapshot of the expiration counters.
+// It acquires the keyspace lock itself.
+func (ks *Keyspace) ExpireStats() ExpireStats {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	return ks.stats
+}
+
+// LookupStats returns a snapshot of the lookup counters.
+// It acquires the keyspace lock itself.
+func (ks *Keyspace) LookupStats() LookupStats {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	return ks.lookups
+}
+
+// ResetStats sets the expiration and lookup counters back to zero.
+// It acquires the keyspace lock itself.
+func (ks *Keyspace) ResetStats() {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.stats = ExpireStats{}
+	ks.lookups = LookupStats{}
+}
+
+// ActiveExpireCycle samples keys with a timeout and deletes the expired ones.
+// Sampling is repeated as long as a significant share of the sampled keys were expired
+// and the cycle has not used up its time budget.
+// It acquires the keyspace lock itself and returns the number of keys deleted.
+func (ks *Keyspace) ActiveExpireCycle() int {
+	if ks.activeExpireDisabled.Load() {
+		return 0
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	start := ks.clock.Now()
+	totalSampled := 0
+	totalExpired := 0
+	var ttlSum, ttlSamples int64
+
+	for {
+		sampled := 0
+		expired := 0
+		now := ks.Now()
+
+		// Map iteration order is randomized, which gives us a cheap random sample
+		for key, item := range ks.volatile {
+			if sampled == activeExpireKeysPerLoop {
+				break
+			}
+			sampled++
+
+			if ks.isExpired(item, now) {
+				ks.expire(key)
+				expired++
+			} else {
+				ttlSum += item.Expiry - now
+				ttlSamples++
+			}
+		}
+
+		totalSampled += sampled
+		totalExpired += expired
+
+		if sampled == 0 || expired*100/sampled <= activeExpireAcceptableStale {
+			break
+		}
+		if ks.clock.Now().Sub(start) > activeExpireTimeLimit {
+			break
+		}
+	}
+
+	// Keep a running average so a single cycle doesn't make the estimate jump
+	current := 0.0
+	if totalSampled > 0 {
+		current = float64(totalExpired) * 100 / float64(totalSampled)
+	}
+	ks.stats.ExpiredStalePerc = current*0.05 + ks.stats.ExpiredStalePerc*0.95
+
+	// The average TTL is smoothed the same way, starting from the first sample
+	switch {
+	case len(ks.volatile) == 0:
+		ks.stats.AvgTTL = 0
+	case ttlSamples > 0 && ks.stats.AvgTTL == 0:
+		ks.stats.AvgTTL = ttlSum / ttlSamples
+	case ttlSamples > 0:
+		ks.stats.AvgTTL = ks.stats.AvgTTL/50*49 + ttlSum/ttlSamples/50
+	}
+
+	return totalExpired
+}
+
+// SetActiveExpire enables or disables the active expire cycle.
+// Expired keys are still deleted when accessed while it is disabled.
+// It may be called without holding the lock.
+func (ks *Keyspace) SetActiveExpire(enabled bool) {
+	ks.activeExpireDisabled.Store(!enabled)
+}
+
+// StartActiveExpire runs ActiveExpireCycle every interval in a background goroutine.
+// The returned function stops it.
+func (ks *Keyspace) StartActiveExpire(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				ks.ActiveExpireCycle()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
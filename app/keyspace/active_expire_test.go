@@ -0,0 +1,163 @@
+package keyspace
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+)
+
+func TestLazyExpiration(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+	ks.Set("key", &Item{Type: TypeString, Value: "value", Expiry: 2000})
+
+	if _, exists := ks.Lookup("key"); !exists {
+		t.Fatal("Expected key to exist before its expiry")
+	}
+
+	c.Advance(2 * time.Second)
+	if _, exists := ks.Lookup("key"); exists {
+		t.Fatal("Expected key to be reported as missing after its expiry")
+	}
+
+	// The lookup must have reclaimed the key
+	if ks.Len() != 0 || ks.VolatileLen() != 0 {
+		t.Errorf("Expected expired key to be deleted, got %d keys and %d volatile keys", ks.Len(), ks.VolatileLen())
+	}
+	if stats := ks.ExpireStats(); stats.ExpiredKeys != 1 {
+		t.Errorf("Expected 1 expired key, got %d", stats.ExpiredKeys)
+	}
+}
+
+func TestActiveExpireCycle(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+
+	// 200 keys expiring soon, 50 keys expiring much later and 50 keys without a timeout
+	for i := 0; i < 200; i++ {
+		ks.Set("short"+strconv.Itoa(i), &Item{Type: TypeString, Value: "v", Expiry: 1500})
+	}
+	for i := 0; i < 50; i++ {
+		ks.Set("long"+strconv.Itoa(i), &Item{Type: TypeString, Value: "v", Expiry: 100000})
+		ks.Set("persistent"+strconv.Itoa(i), &Item{Type: TypeString, Value: "v"})
+	}
+
+	if expired := ks.ActiveExpireCycle(); expired != 0 {
+		t.Fatalf("Expected no key to expire yet, got %d", expired)
+	}
+
+	c.Advance(time.Second)
+
+	// A cycle keeps sampling while many of the sampled keys are expired, and stops early
+	// once the sample looks mostly clean, so a few cycles may be needed to reclaim everything
+	expired := 0
+	for i := 0; i < 100 && ks.VolatileLen() > 50; i++ {
+		expired += ks.ActiveExpireCycle()
+	}
+
+	if expired != 200 {
+		t.Errorf("Expected the 200 expired keys to be reclaimed, got %d", expired)
+	}
+	if ks.Len() != 100 {
+		t.Errorf("Expected 100 keys left, got %d", ks.Len())
+	}
+
+	stats := ks.ExpireStats()
+	if stats.ExpiredKeys != 200 {
+		t.Errorf("Expected 200 expired keys in stats, got %d", stats.ExpiredKeys)
+	}
+	if stats.ExpiredStalePerc <= 0 {
+		t.Errorf("Expected a positive stale percentage, got %f", stats.ExpiredStalePerc)
+	}
+
+	// Keys that are still alive must never be removed
+	for i := 0; i < 50; i++ {
+		if _, exists := ks.Lookup("long" + strconv.Itoa(i)); !exists {
+			t.Fatalf("Expected key long%d to survive the cycle", i)
+		}
+	}
+}
+
+func TestSetExpiry(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+	ks.Set("key", &Item{Type: TypeString, Value: "value"})
+
+	ks.SetExpiry("key", 5000)
+	if ks.VolatileLen() != 1 {
+		t.Errorf("Expected 1 volatile key, got %d", ks.VolatileLen())
+	}
+
+	ks.SetExpiry("key", 0)
+	if ks.VolatileLen() != 0 {
+		t.Errorf("Expected no volatile key, got %d", ks.VolatileLen())
+	}
+}
+
+func TestStartActiveExpire(t *testing.T) {
+	ks := New()
+	ks.Lock()
+	ks.Set("key", &Item{Type: TypeString, Value: "value", Expiry: ks.Now() - 1})
+	ks.Unlock()
+
+	stop := ks.StartActiveExpire(time.Millisecond)
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		ks.Lock()
+		remaining := ks.Len()
+		ks.Unlock()
+		if remaining == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected the background cycle to reclaim the expired key")
+}
+
+func TestSetActiveExpire(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+	ks.Set("key", &Item{Type: TypeString, Value: "value", Expiry: 1500})
+	c.Advance(time.Second)
+
+	ks.SetActiveExpire(false)
+	if expired := ks.ActiveExpireCycle(); expired != 0 || ks.Len() != 1 {
+		t.Errorf("Expected disabled cycle to keep the key, got %d expired and %d keys", expired, ks.Len())
+	}
+
+	ks.SetActiveExpire(true)
+	if expired := ks.ActiveExpireCycle(); expired != 1 || ks.Len() != 0 {
+		t.Errorf("Expected enabled cycle to reclaim the key, got %d expired and %d keys", expired, ks.Len())
+	}
+}
+
+func TestLookupStats(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+	ks.Set("key", &Item{Type: TypeString, Value: "value"})
+	ks.Set("volatile", &Item{Type: TypeString, Value: "value", Expiry: 1500})
+	ks.Set("list", &Item{Type: TypeList, Value: nil})
+
+	ks.LookupRead("key")
+	ks.LookupRead("missing")
+	ks.Lookup("key")
+	if _, err := ks.LookupTypeRead("list", TypeString); err != ErrWrongType {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+	c.Advance(time.Second)
+	ks.LookupRead("volatile")
+
+	stats := ks.LookupStats()
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %+v", stats)
+	}
+
+	ks.ResetStats()
+	if stats := ks.LookupStats(); stats != (LookupStats{}) {
+		t.Errorf("Expected counters to be reset, got %+v", stats)
+	}
+}
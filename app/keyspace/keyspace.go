@@ -0,0 +1,244 @@
+package keyspace
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+)
+
+// Type identifies the kind of value held by a key, as reported by the TYPE command.
+type Type string
+
+const (
+	// TypeString is the type of values created by SET
+	TypeString Type = "string"
+	// TypeList is the type of values created by RPUSH and LPUSH
+	TypeList Type = "list"
+	// TypeStream is the type of values created by XADD
+	TypeStream Type = "stream"
+)
+
+// Types lists every type of value a key can hold.
+var Types = []Type{TypeString, TypeList, TypeStream}
+
+// ErrWrongType is returned when a command is used against a key holding another type of value.
+var ErrWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+type Item struct {
+	// Type is the kind of value stored in the item
+	Type Type
+	// Value holds the value itself: a string, a *list.List or a *stream.Stream depending on Type
+	Value any
+	// Expiry is the expiration time in milliseconds, 0 if the key never expires.
+	// It must only be changed through Keyspace.SetExpiry once the item is stored.
+	Expiry int64
+}
+
+type Keyspace struct {
+	// storage holds every key of the database regardless of its type
+	storage map[string]*Item
+	// volatile holds the keys that have an expiry, sampled by the active expire cycle
+	volatile map[string]*Item
+	// typeCounts holds the number of keys of each type, kept up to date as keys come and go
+	typeCounts map[Type]int
+	// clock tells the current time when checking expiries
+	clock clock.Clock
+	// stats holds the expiration counters
+	stats ExpireStats
+	// lookups counts the hits and misses of read lookups
+	lookups LookupStats
+	// activeExpireDisabled turns ActiveExpireCycle into a no-op, see DEBUG SET-ACTIVE-EXPIRE
+	activeExpireDisabled atomic.Bool
+	// mutex protects access to the storage map and to the values it holds
+	mutex sync.Mutex
+	// readyListeners are called when a key may now be able to serve blocked clients
+	readyListeners []func(key string)
+}
+
+// New creates a new empty Keyspace using the system clock.
+func New() *Keyspace {
+	return NewWithClock(clock.Real{})
+}
+
+// NewWithClock creates a new empty Keyspace reading the current time from c.
+func NewWithClock(c clock.Clock) *Keyspace {
+	return &Keyspace{
+		storage:    make(map[string]*Item),
+		volatile:   make(map[string]*Item),
+		typeCounts: make(map[Type]int),
+		clock:      c,
+	}
+}
+
+// Lock acquires the keyspace lock.
+// It must be held while calling any other method and while reading or modifying item values,
+// unless stated otherwise.
+func (ks *Keyspace) Lock() {
+	ks.mutex.Lock()
+}
+
+// Unlock releases the keyspace lock.
+func (ks *Keyspace) Unlock() {
+	ks.mutex.Unlock()
+}
+
+// Clock returns the clock the keyspace reads the time from.
+// Stores use it for every other time-dependent behaviour, such as blocking timeouts.
+func (ks *Keyspace) Clock() clock.Clock {
+	return ks.clock
+}
+
+// Now returns the current time in milliseconds, as used for expiries.
+// It may be called without holding the lock.
+func (ks *Keyspace) Now() int64 {
+	return ks.clock.Now().UnixMilli()
+}
+
+// Lookup returns the item stored at key.
+// Expired items are deleted and reported as missing.
+func (ks *Keyspace) Lookup(key string) (*Item, bool) {
+	item, exists := ks.storage[key]
+	if !exists {
+		return nil, false
+	}
+
+	if ks.isExpired(item, ks.Now()) {
+		ks.expire(key)
+		return nil, false
+	}
+
+	return item, true
+}
+
+// LookupRead is Lookup for commands that only read the key, it counts keyspace hits and misses.
+func (ks *Keyspace) LookupRead(key string) (*Item, bool) {
+	item, exists := ks.Lookup(key)
+	if exists {
+		ks.lookups.Hits++
+	} else {
+		ks.lookups.Misses++
+	}
+	return item, exists
+}
+
+// LookupTypeRead is LookupType for commands that only read the key, it counts keyspace hits and misses.
+func (ks *Keyspace) LookupTypeRead(key string, t Type) (*Item, error) {
+	item, exists := ks.LookupRead(key)
+	if !exists {
+		return nil, nil
+	}
+
+	if item.Type != t {
+		return nil, ErrWrongType
+	}
+
+	return item, nil
+}
+
+// LookupType returns the item stored at key if it holds a value of the given type.
+// It returns a nil item and no error if the key doesn't exist, and ErrWrongType
+// if the key holds a value of another type.
+func (ks *Keyspace) LookupType(key string, t Type) (*Item, error) {
+	item, exists := ks.Lookup(key)
+	if !exists {
+		return nil, nil
+	}
+
+	if item.Type != t {
+		return nil, ErrWrongType
+	}
+
+	return item, nil
+}
+
+// Set stores item at key, replacing any previous value regardless of its type.
+func (ks *Keyspace) Set(key string, item *Item) {
+	if old, exists := ks.storage[key]; exists {
+		ks.typeCounts[old.Type]--
+	}
+	ks.storage[key] = item
+	ks.typeCounts[item.Type]++
+	if item.Expiry != 0 {
+		ks.volatile[key] = item
+	} else {
+		delete(ks.volatile, key)
+	}
+}
+
+// SetExpiry changes the expiration time in milliseconds of the item stored at key.
+// An expiry of 0 removes the timeout. Does nothing if the key doesn't exist.
+func (ks *Keyspace) SetExpiry(key string, expiry int64) {
+	item, exists := ks.storage[key]
+	if !exists {
+		return
+	}
+
+	item.Expiry = expiry
+	if expiry != 0 {
+		ks.volatile[key] = item
+	} else {
+		delete(ks.volatile, key)
+	}
+}
+
+// Delete removes key from the keyspace.
+// Returns true if the key existed and was not expired, false otherwise.
+func (ks *Keyspace) Delete(key string) bool {
+	_, exists := ks.Lookup(key)
+	if exists {
+		ks.remove(key)
+	}
+	return exists
+}
+
+// Len returns the number of keys stored in the keyspace, including expired keys
+// that have not been reclaimed yet.
+func (ks *Keyspace) Len() int {
+	return len(ks.storage)
+}
+
+// TypeLen returns the number of keys holding a value of type t, including expired keys
+// that have not been reclaimed yet.
+func (ks *Keyspace) TypeLen(t Type) int {
+	return ks.typeCounts[t]
+}
+
+// VolatileLen returns the number of keys that have an expiry.
+func (ks *Keyspace) VolatileLen() int {
+	return len(ks.volatile)
+}
+
+// OnKeyReady registers fn to be called whenever SignalKeyAsReady is called for a key.
+// Blocking commands use it to serve waiting clients once a key receives new data.
+// fn is called with the keyspace lock held.
+func (ks *Keyspace) OnKeyReady(fn func(key string)) {
+	ks.readyListeners = append(ks.readyListeners, fn)
+}
+
+// SignalKeyAsReady notifies the registered listeners that key was created or received new data,
+// e.g. after a push, a rename or a copy.
+func (ks *Keyspace) SignalKeyAsReady(key string) {
+	for _, fn := range ks.readyListeners {
+		fn(key)
+	}
+}
+
+// isExpired reports whether item has a timeout that is already in the past.
+func (ks *Keyspace) isExpired(item *Item, now int64) bool {
+	return item.Expiry != 0 && now > item.Expiry
+}
+
+// expire deletes an expired key and accounts for it in the stats.
+func (ks *Keyspace) expire(key string) {
+	ks.remove(key)
+	ks.stats.ExpiredKeys++
+}
+
+// remove deletes key from the keyspace, it must be stored.
+func (ks *Keyspace) remove(key string) {
+	ks.typeCounts[ks.storage[key].Type]--
+	delete(ks.storage, key)
+	delete(ks.volatile, key)
+}
@@ -0,0 +1,91 @@
+package keyspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+)
+
+func TestLookupType(t *testing.T) {
+	ks := New()
+	ks.Set("str", &Item{Type: TypeString, Value: "value"})
+
+	item, err := ks.LookupType("str", TypeString)
+	if err != nil || item == nil || item.Value != "value" {
+		t.Errorf("Expected string item, got %v (%v)", item, err)
+	}
+
+	item, err = ks.LookupType("str", TypeList)
+	if err != ErrWrongType || item != nil {
+		t.Errorf("Expected ErrWrongType, got %v (%v)", item, err)
+	}
+
+	item, err = ks.LookupType("missing", TypeList)
+	if err != nil || item != nil {
+		t.Errorf("Expected missing key to return nil item and no error, got %v (%v)", item, err)
+	}
+}
+
+func TestLookupExpired(t *testing.T) {
+	ks := New()
+	ks.Set("expired", &Item{Type: TypeString, Value: "value", Expiry: time.Now().UnixMilli() - 1})
+	ks.Set("alive", &Item{Type: TypeString, Value: "value", Expiry: time.Now().UnixMilli() + 60000})
+
+	if _, exists := ks.Lookup("expired"); exists {
+		t.Error("Expected expired key to be reported as missing")
+	}
+	if _, exists := ks.Lookup("alive"); !exists {
+		t.Error("Expected key with a future expiry to exist")
+	}
+}
+
+func TestSetReplacesAnyType(t *testing.T) {
+	ks := New()
+	ks.Set("key", &Item{Type: TypeList, Value: nil})
+	ks.Set("key", &Item{Type: TypeString, Value: "value"})
+
+	item, exists := ks.Lookup("key")
+	if !exists || item.Type != TypeString {
+		t.Errorf("Expected key to hold a string, got %v", item)
+	}
+	if ks.Len() != 1 {
+		t.Errorf("Expected 1 key, got %d", ks.Len())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ks := New()
+	ks.Set("key", &Item{Type: TypeString, Value: "value"})
+
+	if !ks.Delete("key") {
+		t.Error("Expected Delete to report an existing key")
+	}
+	if ks.Delete("key") {
+		t.Error("Expected Delete to report a missing key")
+	}
+	if ks.Len() != 0 {
+		t.Errorf("Expected empty keyspace, got %d keys", ks.Len())
+	}
+}
+
+func TestTypeLen(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1000))
+	ks := NewWithClock(c)
+	ks.Set("a", &Item{Type: TypeString, Value: "value"})
+	ks.Set("b", &Item{Type: TypeString, Value: "value", Expiry: 1500})
+	ks.Set("c", &Item{Type: TypeList, Value: nil})
+
+	// Overwriting a key moves it to the new type
+	ks.Set("a", &Item{Type: TypeList, Value: nil})
+	if ks.TypeLen(TypeString) != 1 || ks.TypeLen(TypeList) != 2 {
+		t.Errorf("Expected 1 string and 2 lists, got %d and %d", ks.TypeLen(TypeString), ks.TypeLen(TypeList))
+	}
+
+	ks.Delete("c")
+	c.Advance(time.Second)
+	ks.ActiveExpireCycle()
+	if ks.TypeLen(TypeString) != 0 || ks.TypeLen(TypeList) != 1 {
+		t.Errorf("Expected no string and 1 list, got %d and %d", ks.TypeLen(TypeString), ks.TypeLen(TypeList))
+	}
+}
@@ -1,6 +1,8 @@
 package list
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"time"
 
@@ -9,28 +11,38 @@ import (
 
 // BLPop removes and returns the first element of the list stored at key,
 // blocking if the list is empty.
+// block is called only when every list is empty, right before waiting, and returns the
+// context the wait ends early with once cancelled: a context.DeadlineExceeded cause replies
+// as if the timeout expired, any other cause is replied as an error.
 // Example: BLPOP mylist 0
-func (s *Store) BLPop(row []string) string {
+func (s *Store) BLPop(block func() context.Context, w *resp.Writer, row []string) {
 	// Check if there are enough arguments
 	if len(row) < 3 {
-		return resp.MakeError("ERR wrong number of arguments for 'blpop' command")
+		w.WriteError("ERR wrong number of arguments for 'blpop' command")
+		return
 	}
 
 	// Parse timeout as float
 	timeoutStr := row[len(row)-1]
 	timeoutSeconds, err := strconv.ParseFloat(timeoutStr, 64)
 	if err != nil || timeoutSeconds < 0 {
-		return resp.MakeError("ERR timeout is not a float or out of range")
+		w.WriteError("ERR timeout is not a float or out of range")
+		return
 	}
 
 	// Get the keys
 	keys := row[1 : len(row)-1]
 
-	s.mutex.Lock()
+	s.keyspace.Lock()
 	// Check each list for an element
 	for _, key := range keys {
-		l, exists := s.storage[key]
-		if exists && l.Len() > 0 {
+		l, err := s.lookupList(key)
+		if err != nil {
+			s.keyspace.Unlock()
+			w.WriteError(err.Error())
+			return
+		}
+		if l != nil && l.Len() > 0 {
 			// Pop the first element
 			front := l.Front()
 			element := front.Value.(string)
@@ -38,12 +50,13 @@ func (s *Store) BLPop(row []string) string {
 
 			// Clean up empty list
 			if l.Len() == 0 {
-				delete(s.storage, key)
+				s.keyspace.Delete(key)
 			}
 
-			s.mutex.Unlock()
+			s.keyspace.Unlock()
 			// Return the key and element as a RESP array
-			return resp.MakeArray([]string{key, element})
+			w.WriteBulkArray([]string{key, element})
+			return
 		}
 	}
 
@@ -55,12 +68,15 @@ func (s *Store) BLPop(row []string) string {
 	for _, key := range keys {
 		s.blockingClients[key] = append(s.blockingClients[key], blockingClient)
 	}
-	s.mutex.Unlock()
+	s.blocked.Add(1)
+	s.keyspace.Unlock()
 
-	// Define a cleanup function to remove the client from all keys
-	cleanup := func() {
-		s.mutex.Lock()
-		defer s.mutex.Unlock()
+	// stopWaiting removes the client from all keys and reports whether it was served meanwhile.
+	// Both happen under the keyspace lock so an element can't be handed over once it returns.
+	stopWaiting := func() bool {
+		s.keyspace.Lock()
+		defer s.keyspace.Unlock()
+		s.blocked.Add(-1)
 		for _, key := range keys {
 			clients := s.blockingClients[key]
 			for i, client := range clients {
@@ -75,28 +91,40 @@ func (s *Store) BLPop(row []string) string {
 				delete(s.blockingClients, key)
 			}
 		}
+		return blockingClient.served
 	}
-	defer cleanup()
 
-	// Block until an element is available or timeout expires
-	var result BlockingResult
-	if timeoutSeconds == 0 {
-		// Indefinite blocking
-		result = <-blockingClient.Waiting
-	} else {
-		// Blocking with timeout
-		timer := time.NewTimer(time.Duration(timeoutSeconds * float64(time.Second)))
+	// Block until an element is available, the timeout expires or the client is unblocked.
+	// A zero timeout blocks indefinitely, receiving from a nil channel never succeeds.
+	var timeout <-chan time.Time
+	if timeoutSeconds > 0 {
+		timer := s.keyspace.Clock().NewTimer(time.Duration(timeoutSeconds * float64(time.Second)))
 		defer timer.Stop()
+		timeout = timer.C()
+	}
 
-		select {
-		case result = <-blockingClient.Waiting:
-			// Element received
-		case <-timer.C:
-			// Timeout expired
-			return resp.MakeNullArray()
-		}
+	ctx := block()
+	var cause error
+	select {
+	case result := <-blockingClient.Waiting:
+		stopWaiting()
+		w.WriteBulkArray([]string{result.Key, result.Value})
+		return
+	case <-timeout:
+		cause = context.DeadlineExceeded
+	case <-ctx.Done():
+		cause = context.Cause(ctx)
 	}
 
-	// Return the key and element
-	return resp.MakeArray([]string{result.Key, result.Value})
+	// An element handed over right before giving up must still be returned, or it would be lost
+	if stopWaiting() {
+		result := <-blockingClient.Waiting
+		w.WriteBulkArray([]string{result.Key, result.Value})
+		return
+	}
+	if errors.Is(cause, context.DeadlineExceeded) {
+		w.WriteNullArray()
+		return
+	}
+	w.WriteError(cause.Error())
 }
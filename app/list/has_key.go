@@ -1,11 +1,15 @@
 package list
 
-// HasKey checks if a key exists in the list store.
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+)
+
+// HasKey checks if a key holding a list exists.
 // Returns true if the key exists, false otherwise.
 func (s *Store) HasKey(key string) bool {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	_, exists := s.storage[key]
-	return exists
+	item, exists := s.keyspace.Lookup(key)
+	return exists && item.Type == keyspace.TypeList
 }
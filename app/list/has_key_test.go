@@ -2,13 +2,16 @@ package list
 
 import (
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 func TestHasKey_ExistingKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create a list
-	store.RPush([]string{"RPUSH", "mylist", "value1"})
+	call(store.RPush, []string{"RPUSH", "mylist", "value1"})
 
 	// Test HasKey
 	if !store.HasKey("mylist") {
@@ -17,7 +20,7 @@ func TestHasKey_ExistingKey(t *testing.T) {
 }
 
 func TestHasKey_MissingKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Test HasKey on non-existent key
 	if store.HasKey("missing_list") {
@@ -26,11 +29,11 @@ func TestHasKey_MissingKey(t *testing.T) {
 }
 
 func TestHasKey_EmptyList(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create a list and then pop all elements
-	store.RPush([]string{"RPUSH", "mylist", "value1"})
-	store.LPop([]string{"LPOP", "mylist"})
+	call(store.RPush, []string{"RPUSH", "mylist", "value1"})
+	call(store.LPop, []string{"LPOP", "mylist"})
 
 	// List should not exist after all elements are removed
 	if store.HasKey("mylist") {
@@ -39,12 +42,12 @@ func TestHasKey_EmptyList(t *testing.T) {
 }
 
 func TestHasKey_MultipleKeys(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create multiple lists
-	store.RPush([]string{"RPUSH", "list1", "value1"})
-	store.RPush([]string{"RPUSH", "list2", "value2"})
-	store.LPush([]string{"LPUSH", "list3", "value3"})
+	call(store.RPush, []string{"RPUSH", "list1", "value1"})
+	call(store.RPush, []string{"RPUSH", "list2", "value2"})
+	call(store.LPush, []string{"LPUSH", "list3", "value3"})
 
 	// Test all keys exist
 	if !store.HasKey("list1") {
@@ -64,10 +67,10 @@ func TestHasKey_MultipleKeys(t *testing.T) {
 }
 
 func TestHasKey_AfterMultiplePushes(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create a list with multiple elements
-	store.RPush([]string{"RPUSH", "mylist", "value1", "value2", "value3"})
+	call(store.RPush, []string{"RPUSH", "mylist", "value1", "value2", "value3"})
 
 	// Key should exist
 	if !store.HasKey("mylist") {
@@ -76,13 +79,13 @@ func TestHasKey_AfterMultiplePushes(t *testing.T) {
 }
 
 func TestHasKey_AfterPartialPop(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create a list with multiple elements
-	store.RPush([]string{"RPUSH", "mylist", "value1", "value2", "value3"})
+	call(store.RPush, []string{"RPUSH", "mylist", "value1", "value2", "value3"})
 
 	// Pop one element
-	store.LPop([]string{"LPOP", "mylist"})
+	call(store.LPop, []string{"LPOP", "mylist"})
 
 	// List should still exist
 	if !store.HasKey("mylist") {
@@ -91,13 +94,13 @@ func TestHasKey_AfterPartialPop(t *testing.T) {
 }
 
 func TestHasKey_DifferentListOperations(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Create list with RPUSH
-	store.RPush([]string{"RPUSH", "list1", "value1"})
+	call(store.RPush, []string{"RPUSH", "list1", "value1"})
 
 	// Create list with LPUSH
-	store.LPush([]string{"LPUSH", "list2", "value2"})
+	call(store.LPush, []string{"LPUSH", "list2", "value2"})
 
 	// Both should exist
 	if !store.HasKey("list1") {
@@ -107,3 +110,8 @@ func TestHasKey_DifferentListOperations(t *testing.T) {
 		t.Error("Expected HasKey to return true for list created with LPUSH")
 	}
 }
+
+// call runs a handler against an in-memory writer and returns the reply it wrote.
+func call(handler func(*resp.Writer, []string), args []string) string {
+	return resp.Record(func(w *resp.Writer) { handler(w, args) })
+}
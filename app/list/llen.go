@@ -6,24 +6,30 @@ import (
 
 // LLen returns the length of the list stored at key.
 // Example: LLEN mylist
-func (s *Store) LLen(row []string) string {
+func (s *Store) LLen(w *resp.Writer, row []string) {
 	// Check if there are enough arguments
 	if len(row) != 2 {
-		return resp.MakeError("ERR wrong number of arguments for 'llen' command")
+		w.WriteError("ERR wrong number of arguments for 'llen' command")
+		return
 	}
 
 	key := row[1]
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
 	// Get the list length
-	list, exists := s.storage[key]
-	if !exists {
+	list, err := s.lookupListRead(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
+	}
+	if list == nil {
 		// If list doesn't exist, return 0
-		return resp.MakeInteger(0)
+		w.WriteInteger(0)
+		return
 	}
 
 	// Return the length of the list as a RESP integer
-	return resp.MakeInteger(list.Len())
+	w.WriteInteger(int64(list.Len()))
 }
@@ -8,9 +8,10 @@ import (
 
 // LPop removes and returns the first elements of the list stored at key.
 // Example: LPOP mylist
-func (s *Store) LPop(row []string) string {
+func (s *Store) LPop(w *resp.Writer, row []string) {
 	if len(row) < 2 {
-		return resp.MakeError("ERR wrong number of arguments for 'lpop' command")
+		w.WriteError("ERR wrong number of arguments for 'lpop' command")
+		return
 	}
 
 	key := row[1]
@@ -23,17 +24,23 @@ func (s *Store) LPop(row []string) string {
 		var err error
 		count, err = strconv.Atoi(row[2])
 		if err != nil || count < 0 {
-			return resp.MakeError("ERR value is not an integer or out of range")
+			w.WriteError("ERR value is not an integer or out of range")
+			return
 		}
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
 	// Check if list exists
-	l, exists := s.storage[key]
-	if !exists || l.Len() == 0 {
-		return resp.MakeNullBulkString()
+	l, err := s.lookupList(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
+	}
+	if l == nil || l.Len() == 0 {
+		w.WriteNullBulk()
+		return
 	}
 
 	// Determine how many elements to actually remove
@@ -50,10 +57,11 @@ func (s *Store) LPop(row []string) string {
 
 		// Clean up empty list
 		if l.Len() == 0 {
-			delete(s.storage, key)
+			s.keyspace.Delete(key)
 		}
 
-		return resp.MakeBulkString(val)
+		w.WriteBulk(val)
+		return
 	}
 
 	// Remove elements from the front
@@ -67,9 +75,9 @@ func (s *Store) LPop(row []string) string {
 
 	// Clean up empty list
 	if l.Len() == 0 {
-		delete(s.storage, key)
+		s.keyspace.Delete(key)
 	}
 
 	// Return as RESP array
-	return resp.MakeArray(removed)
+	w.WriteBulkArray(removed)
 }
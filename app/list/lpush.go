@@ -1,43 +1,42 @@
 package list
 
 import (
-	"container/list"
-
 	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 // LPush inserts one or more elements at the head of a list.
 // Example: LPUSH mylist "world"
-func (s *Store) LPush(row []string) string {
+func (s *Store) LPush(w *resp.Writer, row []string) {
 	// Check if there are enough arguments
 	if len(row) < 3 {
-		return resp.MakeError("ERR wrong number of arguments for 'lpush' command")
+		w.WriteError("ERR wrong number of arguments for 'lpush' command")
+		return
 	}
 
 	key := row[1]
 	elements := row[2:]
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
 	// Initialize list if it doesn't exist
-	if _, exists := s.storage[key]; !exists {
-		s.storage[key] = list.New()
+	l, err := s.lookupOrCreateList(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
 	}
 
-	l := s.storage[key]
-	// Prepend elements. Redis LPUSH appends elements to the head.
-	// LPUSH mylist A B C -> C is head, B second, A third.
-	// So we push A, then B, then C to Front.
-	// Wait, code says: elements are inserted one after the other to the head.
-	// If I push A (Front: A), then B (Front: B, A), then C (Front: C, B, A).
-	// So yes, iterating normally and PushFront works.
+	// Prepend elements. LPUSH mylist A B C leaves C at the head, then B, then A,
+	// so pushing each element to the front in order gives the right result.
 	for _, element := range elements {
 		l.PushFront(element)
 	}
 
-	// Original LPush did NOT handle blocking clients. Assuming this is intended for now.
-	// If we needed to handle them, we would do it here.
+	// Calculate the new length of the list
+	newLength := l.Len()
+
+	// Hand the new elements over to clients blocked in BLPOP
+	s.keyspace.SignalKeyAsReady(key)
 
-	return resp.MakeInteger(l.Len())
+	w.WriteInteger(int64(newLength))
 }
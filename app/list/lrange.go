@@ -8,10 +8,11 @@ import (
 
 // LRange returns the specified elements of the list stored at key.
 // Example: LRANGE mylist 0 -1
-func (s *Store) LRange(row []string) string {
+func (s *Store) LRange(w *resp.Writer, row []string) {
 	// Check if there are enough arguments
 	if len(row) != 4 {
-		return resp.MakeError("ERR wrong number of arguments for 'lrange' command")
+		w.WriteError("ERR wrong number of arguments for 'lrange' command")
+		return
 	}
 
 	key := row[1]
@@ -21,23 +22,30 @@ func (s *Store) LRange(row []string) string {
 	// Parse start index with negative index support
 	start, err := strconv.Atoi(startStr)
 	if err != nil {
-		return resp.MakeError("ERR invalid start index")
+		w.WriteError("ERR invalid start index")
+		return
 	}
 
 	// Parse stop index with negative index support
 	stop, err := strconv.Atoi(stopStr)
 	if err != nil {
-		return resp.MakeError("ERR invalid stop index")
+		w.WriteError("ERR invalid stop index")
+		return
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
 	// Retrieve the list
-	list, exists := s.storage[key]
-	if !exists {
+	list, err := s.lookupListRead(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
+	}
+	if list == nil {
 		// If list doesn't exist, return an empty array
-		return resp.MakeEmptyArray()
+		w.WriteArrayHeader(0)
+		return
 	}
 
 	// Calculate the length of the list
@@ -68,12 +76,14 @@ func (s *Store) LRange(row []string) string {
 
 	// Check if start index is out of bounds
 	if start >= listLength {
-		return resp.MakeEmptyArray()
+		w.WriteArrayHeader(0)
+		return
 	}
 
 	// Check if start index is greater than stop index
 	if start > stop {
-		return resp.MakeEmptyArray()
+		w.WriteArrayHeader(0)
+		return
 	}
 
 	// Extract the sublist
@@ -81,7 +91,6 @@ func (s *Store) LRange(row []string) string {
 	// But for now, simple implementation from Front()
 
 	count := stop - start + 1
-	subList := make([]string, 0, count)
 
 	e := list.Front()
 	// Skip 'start' elements
@@ -91,14 +100,10 @@ func (s *Store) LRange(row []string) string {
 		}
 	}
 
-	// Read 'count' elements
+	// Stream 'count' elements straight to the client, stop is within the list bounds
+	w.WriteArrayHeader(count)
 	for i := 0; i < count; i++ {
-		if e != nil {
-			subList = append(subList, e.Value.(string))
-			e = e.Next()
-		}
+		w.WriteBulk(e.Value.(string))
+		e = e.Next()
 	}
-
-	// Construct the RESP array response
-	return resp.MakeArray(subList)
 }
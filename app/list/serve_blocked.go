@@ -0,0 +1,49 @@
+package list
+
+// serveBlockedClients pops elements from the list stored at key and hands them to the
+// clients blocked on that key, longest waiting first.
+// It is registered as a keyspace ready listener, so the keyspace lock is held.
+func (s *Store) serveBlockedClients(key string) {
+	clients, exists := s.blockingClients[key]
+	if !exists {
+		return
+	}
+
+	l, err := s.lookupList(key)
+	if err != nil || l == nil {
+		return
+	}
+
+	// Loop while we have both waiting clients and elements in the list
+	for len(clients) > 0 && l.Len() > 0 {
+		// Wake up the first (longest waiting) blocking client
+		client := clients[0]
+		clients = clients[1:]
+
+		// A client blocked on several keys may already have been served through another key
+		if client.served {
+			continue
+		}
+
+		// Get and remove the first element
+		front := l.Front()
+		val := front.Value.(string)
+		l.Remove(front)
+
+		client.served = true
+		client.Waiting <- BlockingResult{Key: key, Value: val}
+	}
+
+	// Update the blocking clients list
+	s.blockingClients[key] = clients
+
+	// Clean up empty list of blocking clients
+	if len(s.blockingClients[key]) == 0 {
+		delete(s.blockingClients, key)
+	}
+
+	// Clean up empty list if all elements were consumed by blocking clients
+	if l.Len() == 0 {
+		s.keyspace.Delete(key)
+	}
+}
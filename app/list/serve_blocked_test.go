@@ -0,0 +1,65 @@
+package list_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+func TestLPUSHWakesBlockedClient(t *testing.T) {
+	processor := processor.NewProcessor()
+
+	resultChan := make(chan string, 1)
+	go func() {
+		resultChan <- processor.ProcessCommand([]string{"BLPOP", "lpush_list", "0"})
+	}()
+
+	// Give BLPOP time to block
+	time.Sleep(50 * time.Millisecond)
+	result := processor.ProcessCommand([]string{"LPUSH", "lpush_list", "a", "b"})
+	if result != ":2\r\n" {
+		t.Errorf("LPUSH result = %q, want %q", result, ":2\r\n")
+	}
+
+	select {
+	case result := <-resultChan:
+		expected := "*2\r\n$10\r\nlpush_list\r\n$1\r\nb\r\n"
+		if result != expected {
+			t.Errorf("BLPOP result = %q, want %q", result, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP was not woken by LPUSH")
+	}
+}
+
+func TestClientBlockedOnSeveralKeysIsServedOnce(t *testing.T) {
+	processor := processor.NewProcessor()
+
+	resultChan := make(chan string, 1)
+	go func() {
+		resultChan <- processor.ProcessCommand([]string{"BLPOP", "first", "second", "0"})
+	}()
+
+	// Give BLPOP time to block
+	time.Sleep(50 * time.Millisecond)
+	processor.ProcessCommand([]string{"RPUSH", "first", "a"})
+	processor.ProcessCommand([]string{"RPUSH", "second", "b"})
+
+	select {
+	case result := <-resultChan:
+		expected := "*2\r\n$5\r\nfirst\r\n$1\r\na\r\n"
+		if result != expected {
+			t.Errorf("BLPOP result = %q, want %q", result, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP was not woken by RPUSH")
+	}
+
+	// The element pushed to the second key must not be lost
+	result := processor.ProcessCommand([]string{"LRANGE", "second", "0", "-1"})
+	expected := "*1\r\n$1\r\nb\r\n"
+	if result != expected {
+		t.Errorf("LRANGE second = %q, want %q", result, expected)
+	}
+}
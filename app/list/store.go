@@ -2,7 +2,9 @@ package list
 
 import (
 	"container/list"
-	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 type BlockingResult struct {
@@ -15,21 +17,76 @@ type BlockingResult struct {
 type BlockingClient struct {
 	// Waiting is a channel that receives the result when an element is available
 	Waiting chan BlockingResult
+	// served is set once an element has been sent on Waiting, protected by the keyspace lock
+	served bool
 }
 
 type Store struct {
-	// storage holds the key-value pairs for list commands
-	storage map[string]*list.List
-	// blockingClients holds the list of clients waiting for elements on specific keys
+	// keyspace holds the values of all keys, list values are stored as *list.List
+	keyspace *keyspace.Keyspace
+	// blockingClients holds the list of clients waiting for elements on specific keys.
+	// It is protected by the keyspace lock.
 	blockingClients map[string][]*BlockingClient
-	// mutex protects access to the storage and blockingClients map
-	mutex sync.Mutex
+	// blocked counts the clients currently blocked in BLPOP, readable without the keyspace lock
+	blocked atomic.Int64
 }
 
-// NewStore creates a new Store instance with initialized storage and blocking clients.
-func NewStore() *Store {
-	return &Store{
-		storage:         make(map[string]*list.List),
+// NewStore creates a new Store instance backed by the given keyspace.
+func NewStore(ks *keyspace.Keyspace) *Store {
+	s := &Store{
+		keyspace:        ks,
 		blockingClients: make(map[string][]*BlockingClient),
 	}
+	ks.OnKeyReady(s.serveBlockedClients)
+	return s
+}
+
+// BlockedClients returns the number of clients currently blocked waiting for list elements.
+func (s *Store) BlockedClients() int64 {
+	return s.blocked.Load()
+}
+
+// BlockingKeys returns the number of distinct keys that blocked clients are waiting on.
+func (s *Store) BlockingKeys() int {
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+	return len(s.blockingClients)
+}
+
+// lookupList returns the list stored at key, or nil if the key doesn't exist.
+// Returns keyspace.ErrWrongType if the key holds another type of value.
+// The keyspace lock must be held.
+func (s *Store) lookupList(key string) (*list.List, error) {
+	item, err := s.keyspace.LookupType(key, keyspace.TypeList)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return item.Value.(*list.List), nil
+}
+
+// lookupListRead is lookupList for commands that only read the list, counting keyspace hits and misses.
+// The keyspace lock must be held.
+func (s *Store) lookupListRead(key string) (*list.List, error) {
+	item, err := s.keyspace.LookupTypeRead(key, keyspace.TypeList)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return item.Value.(*list.List), nil
+}
+
+// lookupOrCreateList returns the list stored at key, creating an empty one if the key doesn't exist.
+// Returns keyspace.ErrWrongType if the key holds another type of value.
+// The keyspace lock must be held.
+func (s *Store) lookupOrCreateList(key string) (*list.List, error) {
+	l, err := s.lookupList(key)
+	if err != nil || l != nil {
+		return l, err
+	}
+
+	l = list.New()
+	s.keyspace.Set(key, &keyspace.Item{
+		Type:  keyspace.TypeList,
+		Value: l,
+	})
+	return l, nil
 }
@@ -0,0 +1,162 @@
+// Package logging builds the server logger on top of log/slog, using the Redis log levels.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log levels, from the most to the least verbose.
+const (
+	// LevelDebug logs everything, including information only useful when developing
+	LevelDebug = slog.LevelDebug
+	// LevelVerbose logs rarely useful information such as connections and disconnections
+	LevelVerbose = slog.Level(-2)
+	// LevelNotice logs what is useful in production, e.g. startup and slow commands
+	LevelNotice = slog.LevelInfo
+	// LevelWarning only logs very important or critical messages
+	LevelWarning = slog.LevelWarn
+)
+
+// Output formats.
+const (
+	// FormatText writes one "key=value" line per record
+	FormatText = "text"
+	// FormatJSON writes one JSON object per record
+	FormatJSON = "json"
+)
+
+// levelNames maps the names used by the loglevel directive to their level.
+var levelNames = map[string]slog.Level{
+	"debug":   LevelDebug,
+	"verbose": LevelVerbose,
+	"notice":  LevelNotice,
+	"warning": LevelWarning,
+}
+
+// ParseLevel returns the level with the given name, case insensitively.
+// Example: ParseLevel("notice") -> LevelNotice
+func ParseLevel(name string) (slog.Level, error) {
+	level, exists := levelNames[strings.ToLower(name)]
+	if !exists {
+		return 0, fmt.Errorf("invalid log level '%s'", name)
+	}
+	return level, nil
+}
+
+// LevelName returns the name of level, rounding down to the closest known level.
+// Example: LevelName(slog.LevelError) -> "warning"
+func LevelName(level slog.Level) string {
+	switch {
+	case level >= LevelWarning:
+		return "warning"
+	case level >= LevelNotice:
+		return "notice"
+	case level >= LevelVerbose:
+		return "verbose"
+	default:
+		return "debug"
+	}
+}
+
+// Options configures a Logger created with New.
+type Options struct {
+	// Level is the name of the minimum level logged, see ParseLevel
+	Level string
+	// File is the path of the file logs are appended to, empty to log to the standard output
+	File string
+	// Format is FormatText or FormatJSON
+	Format string
+}
+
+// Logger is a slog.Logger whose level can be changed at runtime.
+type Logger struct {
+	*slog.Logger
+	// level is shared with the handler so SetLevel applies to every record logged afterwards
+	level *slog.LevelVar
+	// file is the log file, nil when logging to the standard output
+	file *os.File
+}
+
+// New creates a Logger writing to the file or standard output selected by opts.
+func New(opts Options) (*Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if opts.File != "" {
+		file, err = os.OpenFile(opts.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("can't open the log file: %w", err)
+		}
+		out = file
+	}
+
+	l := &Logger{level: new(slog.LevelVar), file: file}
+	l.level.Set(level)
+	handler, err := newHandler(out, opts.Format, l.level)
+	if err != nil {
+		if file != nil {
+			_ = file.Close()
+		}
+		return nil, err
+	}
+	l.Logger = slog.New(handler)
+	return l, nil
+}
+
+// newHandler creates the slog handler for format, printing levels with their Redis names.
+func newHandler(out io.Writer, format string, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				a.Value = slog.StringValue(LevelName(a.Value.Any().(slog.Level)))
+			}
+			return a
+		},
+	}
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.NewTextHandler(out, opts), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(out, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid log format '%s'", format)
+	}
+}
+
+// SetLevel changes the minimum level logged.
+func (l *Logger) SetLevel(name string) error {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	l.level.Set(level)
+	return nil
+}
+
+// Level returns the minimum level logged.
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// Close closes the log file, if any.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Verbose logs msg at LevelVerbose, for which slog.Logger has no method.
+func Verbose(l *slog.Logger, msg string, args ...any) {
+	l.Log(context.Background(), LevelVerbose, msg, args...)
+}
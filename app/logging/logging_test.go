@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected slog.Level
+	}{
+		{"debug", LevelDebug},
+		{"verbose", LevelVerbose},
+		{"NOTICE", LevelNotice},
+		{"warning", LevelWarning},
+	}
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.name)
+		if err != nil || level != tt.expected {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", tt.name, level, err, tt.expected)
+		}
+		if name := LevelName(level); name != strings.ToLower(tt.name) {
+			t.Errorf("LevelName(%v) = %q, want %q", level, name, strings.ToLower(tt.name))
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an unknown level to be rejected")
+	}
+	if name := LevelName(slog.LevelError); name != "warning" {
+		t.Errorf("Expected errors to be reported as warnings, got %q", name)
+	}
+}
+
+// readLines returns the lines of the log file at path.
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestLoggerWritesJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.log")
+	l, err := New(Options{Level: "notice", File: path, Format: FormatJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Verbose(l.Logger, "hidden")
+	l.Info("Slow command", "client_id", 7)
+	if err := l.SetLevel("verbose"); err != nil {
+		t.Fatal(err)
+	}
+	Verbose(l.Logger, "Accepted connection")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %q", lines)
+	}
+	var record struct {
+		Level    string `json:"level"`
+		Msg      string `json:"msg"`
+		ClientID int    `json:"client_id"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Level != "notice" || record.Msg != "Slow command" || record.ClientID != 7 {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if !strings.Contains(lines[1], `"level":"verbose"`) {
+		t.Errorf("Expected a verbose record, got %q", lines[1])
+	}
+}
+
+func TestLoggerText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.log")
+	l, err := New(Options{Level: "warning", File: path, Format: FormatText})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Info("hidden")
+	l.Warn("Failed to bind", "addr", "127.0.0.1:6379")
+
+	lines := readLines(t, path)
+	if len(lines) != 1 || !strings.Contains(lines[0], `level=warning msg="Failed to bind" addr=127.0.0.1:6379`) {
+		t.Errorf("Unexpected log lines %q", lines)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New(Options{Level: "notice", Format: "xml"}); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+	if _, err := New(Options{Level: "notice", Format: FormatText, File: filepath.Join(t.TempDir(), "missing", "redis.log")}); err == nil {
+		t.Error("Expected an unwritable log file to be rejected")
+	}
+}
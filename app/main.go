@@ -1,35 +1,69 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/logging"
 	"github.com/codecrafters-io/redis-starter-go/app/processor"
+	"github.com/codecrafters-io/redis-starter-go/app/server"
 )
 
-// Ensures gofmt doesn't remove the "net" and "os" imports in stage 1 (feel free to remove this!)
-var _ = net.Listen
-var _ = os.Exit
-
 func main() {
-	// You can use print statements as follows for debugging, they'll be visible when running tests.
-	fmt.Println("Logs from your program will appear here!")
+	if len(os.Args) == 2 && (os.Args[1] == "-v" || os.Args[1] == "--version") {
+		fmt.Println("Redis server v=" + processor.Version)
+		return
+	}
 
-	l, err := net.Listen("tcp", "0.0.0.0:6379")
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		fmt.Println("Failed to bind to port 6379")
+		fmt.Println("Invalid configuration:", err)
 		os.Exit(1)
 	}
-	proc := processor.NewProcessor()
 
-	for {
-		conn, err := l.Accept()
-		if err != nil {
-			fmt.Println("Error accepting connection: ", err.Error())
-			os.Exit(1)
+	logger, err := logging.New(logging.Options{Level: cfg.LogLevel, File: cfg.LogFile, Format: cfg.LogFormat})
+	if err != nil {
+		fmt.Println("Invalid configuration:", err)
+		os.Exit(1)
+	}
+	defer logger.Close()
+
+	srv := server.NewWithOptions(server.Options{Config: cfg, Logger: logger.Logger})
+	srv.Processor.Config.OnChange(func(c *config.Config) {
+		// loglevel is validated by the config store, it can't be rejected here
+		_ = logger.SetLevel(c.LogLevel)
+	})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-signals
+		logger.Warn("Received signal, scheduling shutdown...", "signal", sig.String())
+		// CONFIG SET may have changed shutdown-timeout since the start
+		timeout := time.Duration(srv.Processor.Config.Current().ShutdownTimeout) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Warn("Closed the connections of commands still running after the shutdown timeout")
 		}
+	}()
 
-		go handleConnection(proc, conn)
+	logger.Info("Server initialized", "version", processor.Version, "pid", os.Getpid(), "config_file", cfg.File)
+	if err := srv.ListenAndServe(); !errors.Is(err, server.ErrServerClosed) {
+		fatal(logger.Logger, "Failed to serve", "error", err)
 	}
+	logger.Warn("Redis is now ready to exit, bye bye...")
+}
+
+// fatal logs msg as a warning and exits, as the server can't run.
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Warn(msg, args...)
+	os.Exit(1)
 }
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds in seconds of the buckets used for command latencies,
+// from 10 microseconds to 1 second.
+var LatencyBuckets = []float64{
+	0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005,
+	0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1,
+}
+
+// Histogram counts observed durations in cumulative buckets, as a Prometheus histogram.
+// It is safe for concurrent use and never allocates when observing.
+type Histogram struct {
+	// bounds are the upper bounds of the buckets in seconds, sorted in increasing order
+	bounds []float64
+	// counts holds the number of observations falling in each bucket, the last one
+	// counting the observations above every bound
+	counts []atomic.Uint64
+	// sum is the total of the observed durations in nanoseconds
+	sum atomic.Int64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds in seconds.
+func NewHistogram(bounds []float64) *Histogram {
+	bounds = append([]float64(nil), bounds...)
+	sort.Float64s(bounds)
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+// Observe records a duration.
+func (h *Histogram) Observe(d time.Duration) {
+	seconds := d.Seconds()
+	i := sort.SearchFloat64s(h.bounds, seconds)
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Reset discards every observation.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i].Store(0)
+	}
+	h.sum.Store(0)
+}
+
+// Snapshot is a point in time copy of a Histogram.
+type Snapshot struct {
+	// Bounds are the upper bounds of the buckets in seconds
+	Bounds []float64
+	// Cumulative holds for each bound the number of observations lower or equal to it
+	Cumulative []uint64
+	// Count is the total number of observations
+	Count uint64
+	// Sum is the total of the observations in seconds
+	Sum float64
+}
+
+// Snapshot returns the current state of the histogram.
+// Concurrent observations may be partially reflected.
+func (h *Histogram) Snapshot() Snapshot {
+	s := Snapshot{
+		Bounds:     h.bounds,
+		Cumulative: make([]uint64, len(h.bounds)),
+		Sum:        time.Duration(h.sum.Load()).Seconds(),
+	}
+	var total uint64
+	for i := range h.bounds {
+		total += h.counts[i].Load()
+		s.Cumulative[i] = total
+	}
+	s.Count = total + h.counts[len(h.bounds)].Load()
+	return s
+}
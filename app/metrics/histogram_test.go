@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{0.01, 0.001, 0.1})
+	h.Observe(500 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(50 * time.Millisecond)
+	h.Observe(time.Second)
+
+	s := h.Snapshot()
+	if !reflect.DeepEqual(s.Bounds, []float64{0.001, 0.01, 0.1}) {
+		t.Errorf("Expected sorted bounds, got %v", s.Bounds)
+	}
+	// Bounds are inclusive, 1ms falls in the 0.001 bucket
+	if !reflect.DeepEqual(s.Cumulative, []uint64{2, 2, 3}) {
+		t.Errorf("Expected cumulative counts [2 2 3], got %v", s.Cumulative)
+	}
+	if s.Count != 4 {
+		t.Errorf("Expected 4 observations, got %d", s.Count)
+	}
+	if s.Sum != 1.0515 {
+		t.Errorf("Expected a sum of 1.0515s, got %v", s.Sum)
+	}
+
+	h.Reset()
+	if s := h.Snapshot(); s.Count != 0 || s.Sum != 0 || s.Cumulative[2] != 0 {
+		t.Errorf("Expected an empty histogram after Reset, got %+v", s)
+	}
+}
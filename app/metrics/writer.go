@@ -0,0 +1,149 @@
+// Package metrics exposes server metrics in the Prometheus text exposition format,
+// using only the standard library.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Metric types, as announced in the TYPE line of a metric family.
+const (
+	// Counter is a value that only goes up, until the server restarts or its stats are reset
+	Counter = "counter"
+	// Gauge is a value that may go up and down
+	Gauge = "gauge"
+	// HistogramType is a set of cumulative buckets with a count and a sum
+	HistogramType = "histogram"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label is a name/value pair identifying a sample within a metric family.
+type Label struct {
+	// Name is the label name, e.g. "cmd"
+	Name string
+	// Value is the label value, escaped when written
+	Value string
+}
+
+// Writer encodes metric families in the text exposition format.
+// Write errors are sticky and returned by Flush.
+type Writer struct {
+	// w buffers the encoded metrics until Flush
+	w *bufio.Writer
+}
+
+// NewWriter creates a Writer on top of w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Flush writes the buffered metrics to the underlying stream.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Family starts a metric family, it must be followed by the samples of the family.
+// Example: Family("redis_connected_clients", "Number of connected clients.", Gauge)
+func (w *Writer) Family(name, help, metricType string) {
+	_, _ = w.w.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	_, _ = w.w.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+// Sample writes one sample of the current family.
+// Example: Sample("redis_keys", 3, Label{"type", "string"})
+func (w *Writer) Sample(name string, value float64, labels ...Label) {
+	_, _ = w.w.WriteString(name)
+	w.writeLabels(labels, "", "")
+	_, _ = w.w.WriteString(" " + formatValue(value) + "\n")
+}
+
+// Histogram writes the buckets, sum and count of a histogram family member.
+func (w *Writer) Histogram(name string, s Snapshot, labels ...Label) {
+	for i, bound := range s.Bounds {
+		_, _ = w.w.WriteString(name + "_bucket")
+		w.writeLabels(labels, "le", formatValue(bound))
+		_, _ = w.w.WriteString(" " + strconv.FormatUint(s.Cumulative[i], 10) + "\n")
+	}
+	_, _ = w.w.WriteString(name + "_bucket")
+	w.writeLabels(labels, "le", "+Inf")
+	_, _ = w.w.WriteString(" " + strconv.FormatUint(s.Count, 10) + "\n")
+
+	_, _ = w.w.WriteString(name + "_sum")
+	w.writeLabels(labels, "", "")
+	_, _ = w.w.WriteString(" " + formatValue(s.Sum) + "\n")
+	_, _ = w.w.WriteString(name + "_count")
+	w.writeLabels(labels, "", "")
+	_, _ = w.w.WriteString(" " + strconv.FormatUint(s.Count, 10) + "\n")
+}
+
+// writeLabels writes labels between braces, followed by extra when its name is not empty.
+func (w *Writer) writeLabels(labels []Label, extraName, extraValue string) {
+	if len(labels) == 0 && extraName == "" {
+		return
+	}
+	_ = w.w.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			_ = w.w.WriteByte(',')
+		}
+		_, _ = w.w.WriteString(l.Name + `="` + escapeLabel(l.Value) + `"`)
+	}
+	if extraName != "" {
+		if len(labels) > 0 {
+			_ = w.w.WriteByte(',')
+		}
+		_, _ = w.w.WriteString(extraName + `="` + extraValue + `"`)
+	}
+	_ = w.w.WriteByte('}')
+}
+
+// formatValue formats a sample value, using the spellings Prometheus expects for special values.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelEscaper escapes backslashes, double quotes and newlines in label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes backslashes and newlines in help texts.
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// escapeLabel escapes a label value for use between double quotes.
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// escapeHelp escapes a help text.
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+// Handler returns an HTTP handler serving the metrics written by collect on every scrape.
+func Handler(collect func(w *Writer)) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			rw.Header().Set("Allow", "GET, HEAD")
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		rw.Header().Set("Content-Type", ContentType)
+		w := NewWriter(rw)
+		collect(w)
+		_ = w.Flush()
+	})
+}
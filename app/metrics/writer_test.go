@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	w.Family("redis_keys", "Number of keys\nby type.", Gauge)
+	w.Sample("redis_keys", 3, Label{Name: "type", Value: "string"})
+	w.Sample("redis_keys", math.Inf(1), Label{Name: "type", Value: `a"b\c`})
+	w.Family("redis_uptime_seconds", "Uptime.", Gauge)
+	w.Sample("redis_uptime_seconds", 1.5)
+
+	h := NewHistogram([]float64{0.001, 0.01})
+	h.Observe(5 * time.Millisecond)
+	w.Family("redis_command_duration_seconds", "Latency.", HistogramType)
+	w.Histogram("redis_command_duration_seconds", h.Snapshot(), Label{Name: "cmd", Value: "get"})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# HELP redis_keys Number of keys\\nby type.\n" +
+		"# TYPE redis_keys gauge\n" +
+		"redis_keys{type=\"string\"} 3\n" +
+		"redis_keys{type=\"a\\\"b\\\\c\"} +Inf\n" +
+		"# HELP redis_uptime_seconds Uptime.\n" +
+		"# TYPE redis_uptime_seconds gauge\n" +
+		"redis_uptime_seconds 1.5\n" +
+		"# HELP redis_command_duration_seconds Latency.\n" +
+		"# TYPE redis_command_duration_seconds histogram\n" +
+		"redis_command_duration_seconds_bucket{cmd=\"get\",le=\"0.001\"} 0\n" +
+		"redis_command_duration_seconds_bucket{cmd=\"get\",le=\"0.01\"} 1\n" +
+		"redis_command_duration_seconds_bucket{cmd=\"get\",le=\"+Inf\"} 1\n" +
+		"redis_command_duration_seconds_sum{cmd=\"get\"} 0.005\n" +
+		"redis_command_duration_seconds_count{cmd=\"get\"} 1\n"
+	if sb.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, sb.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler := Handler(func(w *Writer) {
+		w.Family("up", "Whether the server is up.", Gauge)
+		w.Sample("up", 1)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Expected 200 with the exposition content type, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.HasSuffix(rec.Body.String(), "up 1\n") {
+		t.Errorf("Expected the sample in the body, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected POST to be refused, got %d", rec.Code)
+	}
+}
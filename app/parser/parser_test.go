@@ -2,7 +2,10 @@ package parser
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 func TestParseString(t *testing.T) {
@@ -181,6 +184,56 @@ func TestParseStringEdgeCases(t *testing.T) {
 	})
 }
 
+func TestParseStringBinarySafe(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "Embedded CRLF", value: "line1\r\nline2"},
+		{name: "Only CRLF", value: "\r\n"},
+		{name: "Lone CR", value: "a\rb"},
+		{name: "Lone LF", value: "a\nb"},
+		{name: "NUL bytes", value: "\x00\x01\x00"},
+		{name: "Trailing CRLF", value: "value\r\n"},
+		{name: "Fake RESP header", value: "\r\n$3\r\nfoo\r\n"},
+		{name: "JSON with CRLF", value: "{\r\n  \"a\": 1\r\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"SET", "key", tt.value}
+			input := "*" + strconv.Itoa(len(args)) + "\r\n"
+			for _, arg := range args {
+				input += resp.MakeBulkString(arg)
+			}
+
+			result, err := ParseString(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, args) {
+				t.Errorf("Expected %q, got %q", args, result)
+			}
+		})
+	}
+}
+
+func TestParseStringDeclaredLength(t *testing.T) {
+	t.Run("Payload shorter than declared length", func(t *testing.T) {
+		_, err := ParseString("*1\r\n$10\r\nshort\r\n")
+		if err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	})
+
+	t.Run("Payload longer than declared length", func(t *testing.T) {
+		_, err := ParseString("*1\r\n$3\r\nlonger\r\n")
+		if err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	})
+}
+
 func BenchmarkParseString(b *testing.B) {
 	input := "*4\r\n$2\r\nee\r\n$6\r\njhgjhg\r\n$3\r\nkjg\r\n$2\r\nkl\r\n"
 
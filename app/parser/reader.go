@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// readBufferSize is the size of the buffer used to read from the connection
+	readBufferSize = 16 * 1024
+	// maxMultibulkLength is the largest number of elements accepted in a single command
+	maxMultibulkLength = 1024 * 1024
+	// maxBulkLength is the largest bulk string accepted in a single command (512 MB)
+	maxBulkLength = 512 * 1024 * 1024
+	// maxInlineLength is the longest inline command or protocol header line accepted (64 KB)
+	maxInlineLength = 64 * 1024
+)
+
+// ProtocolError is returned when the client sends data that does not follow the RESP protocol.
+type ProtocolError struct {
+	// msg describes what was wrong with the request
+	msg string
+	// fatal is set when the stream can no longer be decoded
+	fatal bool
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.msg
+}
+
+// Fatal reports whether the rest of the stream can no longer be decoded.
+// The offending request of a non-fatal error has been fully consumed, so the
+// connection can answer with the error and keep reading commands.
+func (e *ProtocolError) Fatal() bool {
+	return e.fatal
+}
+
+// Reader decodes RESP commands from a byte stream.
+// Bytes that were read but not yet consumed are kept for the next call, so a command
+// split across several reads, larger than a single read, or pipelined together with
+// other commands is always returned whole and in order.
+type Reader struct {
+	// rd buffers the data read from the underlying stream
+	rd *bufio.Reader
+}
+
+// NewReader creates a new Reader reading from rd.
+func NewReader(rd io.Reader) *Reader {
+	return &Reader{
+		rd: bufio.NewReaderSize(rd, readBufferSize),
+	}
+}
+
+// Buffered returns the number of bytes that have been read from the stream but not yet decoded.
+// A value of zero means there are no more pipelined commands waiting to be processed.
+func (r *Reader) Buffered() int {
+	return r.rd.Buffered()
+}
+
+// ReadCommand reads the next command from the stream and returns its arguments.
+// Both multibulk requests and inline commands are accepted.
+// Example: "*2\r\n$4\r\nECHO\r\n$2\r\nhi\r\n" -> ["ECHO", "hi"]
+// Example: "ECHO hi\r\n" -> ["ECHO", "hi"]
+func (r *Reader) ReadCommand() ([]string, error) {
+	first, err := r.rd.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] != '*' {
+		return r.readInlineCommand()
+	}
+	return r.readMultibulkCommand()
+}
+
+// readInlineCommand reads a single line of space separated arguments.
+func (r *Reader) readInlineCommand() ([]string, error) {
+	line, err := r.readRawLine()
+	if err != nil {
+		return nil, err
+	}
+
+	// Inline commands may be terminated by a bare LF, e.g. when typed in telnet
+	line = strings.TrimSuffix(line, "\r")
+
+	args, err := SplitArgs(line)
+	if err != nil {
+		return nil, &ProtocolError{msg: err.Error()}
+	}
+
+	return args, nil
+}
+
+// readMultibulkCommand reads a "*<count>\r\n" header followed by count bulk strings.
+func (r *Reader) readMultibulkCommand() ([]string, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count > maxMultibulkLength {
+		return nil, &ProtocolError{msg: "invalid multibulk length", fatal: true}
+	}
+	if count <= 0 {
+		return []string{}, nil
+	}
+
+	result := make([]string, 0, count)
+	for len(result) < count {
+		element, err := r.readBulkString()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, element)
+	}
+
+	return result, nil
+}
+
+// readBulkString reads a single "$<length>\r\n<data>\r\n" element.
+func (r *Reader) readBulkString() (string, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	if len(line) == 0 || line[0] != '$' {
+		return "", &ProtocolError{msg: fmt.Sprintf("expected '$', got '%s'", firstChar(line)), fatal: true}
+	}
+
+	length, err := strconv.Atoi(line[1:])
+	if err != nil || length < 0 || length > maxBulkLength {
+		return "", &ProtocolError{msg: "invalid bulk length", fatal: true}
+	}
+
+	// The payload grows as it arrives, a client announcing a huge length without sending it
+	// can't make the server allocate it up front
+	var payload strings.Builder
+	payload.Grow(min(length, readBufferSize))
+	if _, err := io.CopyN(&payload, r.rd, int64(length)); err != nil {
+		return "", unexpectedEOF(err)
+	}
+	var crlf [2]byte
+	if _, err := io.ReadFull(r.rd, crlf[:]); err != nil {
+		return "", unexpectedEOF(err)
+	}
+	if crlf[0] != '\r' || crlf[1] != '\n' {
+		return "", &ProtocolError{msg: "bulk string is not terminated by CRLF", fatal: true}
+	}
+
+	return payload.String(), nil
+}
+
+// readLine reads a single CRLF terminated line and returns it without the terminator.
+func (r *Reader) readLine() (string, error) {
+	line, err := r.readRawLine()
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(line, "\r") {
+		return "", &ProtocolError{msg: "line is not terminated by CRLF", fatal: true}
+	}
+
+	return line[:len(line)-1], nil
+}
+
+// readRawLine reads up to and including the next LF and returns the line without the LF.
+// Lines longer than maxInlineLength are rejected so a client cannot make the server
+// buffer an unbounded amount of data while looking for the end of a line.
+func (r *Reader) readRawLine() (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.rd.ReadSlice('\n')
+		if len(line)+len(chunk) > maxInlineLength {
+			return "", &ProtocolError{msg: "too big inline request", fatal: true}
+		}
+		line = append(line, chunk...)
+
+		if err == nil {
+			return string(line[:len(line)-1]), nil
+		}
+		if err != bufio.ErrBufferFull {
+			if len(line) > 0 {
+				return "", unexpectedEOF(err)
+			}
+			return "", err
+		}
+	}
+}
+
+// unexpectedEOF converts a clean EOF in the middle of a command into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// firstChar returns the first character of line for use in error messages.
+func firstChar(line string) string {
+	if len(line) == 0 {
+		return ""
+	}
+	return line[:1]
+}
@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderReadCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Single command",
+			input:    "*2\r\n$4\r\nECHO\r\n$5\r\nhello\r\n",
+			expected: []string{"ECHO", "hello"},
+		},
+		{
+			name:     "Empty bulk string",
+			input:    "*2\r\n$4\r\nECHO\r\n$0\r\n\r\n",
+			expected: []string{"ECHO", ""},
+		},
+		{
+			name:     "Zero elements",
+			input:    "*0\r\n",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader(strings.NewReader(tt.input))
+			result, err := reader.ReadCommand()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReaderPartialReads(t *testing.T) {
+	input := "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"
+
+	// OneByteReader returns a single byte per Read call, simulating a command split across segments
+	reader := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+	result, err := reader.ReadCommand()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"SET", "key", "value"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestReaderLargePayload(t *testing.T) {
+	value := strings.Repeat("x", 100*1024)
+	input := "*3\r\n$5\r\nRPUSH\r\n$4\r\nlist\r\n$102400\r\n" + value + "\r\n"
+
+	reader := NewReader(iotest.HalfReader(strings.NewReader(input)))
+	result, err := reader.ReadCommand()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result) != 3 || result[2] != value {
+		t.Errorf("Expected value of length %d, got %d elements", len(value), len(result))
+	}
+}
+
+func TestReaderAnnouncedLengthNotAllocated(t *testing.T) {
+	// A client may announce the largest bulk string accepted and then stop sending
+	input := "*1\r\n$536870912\r\nabc"
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := NewReader(strings.NewReader(input)).ReadCommand()
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1024*1024 {
+		t.Errorf("Expected the announced length not to be allocated up front, got %d bytes allocated", allocated)
+	}
+}
+
+func TestReaderPipelining(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("*3\r\n$5\r\nRPUSH\r\n$4\r\nlist\r\n$1\r\nx\r\n")
+	}
+
+	reader := NewReader(strings.NewReader(sb.String()))
+	for i := 0; i < 1000; i++ {
+		result, err := reader.ReadCommand()
+		if err != nil {
+			t.Fatalf("Command %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(result, []string{"RPUSH", "list", "x"}) {
+			t.Fatalf("Command %d: got %q", i, result)
+		}
+	}
+
+	if _, err := reader.ReadCommand(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF after the last command, got %v", err)
+	}
+}
+
+func TestReaderInlineCommands(t *testing.T) {
+	input := "PING\r\nECHO \"hello world\"\nSET key 'a b'\r\n\r\n*1\r\n$4\r\nPING\r\n"
+	reader := NewReader(strings.NewReader(input))
+
+	expected := [][]string{
+		{"PING"},
+		{"ECHO", "hello world"},
+		{"SET", "key", "a b"},
+		{},
+		{"PING"},
+	}
+	for i, want := range expected {
+		result, err := reader.ReadCommand()
+		if err != nil {
+			t.Fatalf("Command %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("Command %d: expected %q, got %q", i, want, result)
+		}
+	}
+}
+
+func TestReaderInlineErrors(t *testing.T) {
+	t.Run("Unbalanced quotes are recoverable", func(t *testing.T) {
+		reader := NewReader(strings.NewReader("ECHO \"oops\r\nPING\r\n"))
+
+		_, err := reader.ReadCommand()
+		var protocolErr *ProtocolError
+		if !errors.As(err, &protocolErr) {
+			t.Fatalf("Expected protocol error, got %v", err)
+		}
+		if protocolErr.Fatal() {
+			t.Errorf("Expected unbalanced quotes to be recoverable")
+		}
+
+		result, err := reader.ReadCommand()
+		if err != nil || !reflect.DeepEqual(result, []string{"PING"}) {
+			t.Errorf("Expected next command to be PING, got %q (%v)", result, err)
+		}
+	})
+
+	t.Run("Too big inline request", func(t *testing.T) {
+		reader := NewReader(strings.NewReader(strings.Repeat("a", maxInlineLength+1) + "\r\n"))
+
+		_, err := reader.ReadCommand()
+		var protocolErr *ProtocolError
+		if !errors.As(err, &protocolErr) || !protocolErr.Fatal() {
+			t.Errorf("Expected fatal protocol error, got %v", err)
+		}
+	})
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		protocol bool
+	}{
+		{
+			name:     "Invalid multibulk length",
+			input:    "*abc\r\n",
+			protocol: true,
+		},
+		{
+			name:     "Missing bulk string indicator",
+			input:    "*1\r\n:1\r\n",
+			protocol: true,
+		},
+		{
+			name:     "Invalid bulk length",
+			input:    "*1\r\n$-5\r\n",
+			protocol: true,
+		},
+		{
+			name:     "Bulk string longer than declared",
+			input:    "*1\r\n$2\r\nabc\r\n",
+			protocol: true,
+		},
+		{
+			name:     "Truncated command",
+			input:    "*2\r\n$3\r\nGET\r\n$3\r\nke",
+			protocol: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader(strings.NewReader(tt.input))
+			_, err := reader.ReadCommand()
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+
+			var protocolErr *ProtocolError
+			if errors.As(err, &protocolErr) != tt.protocol {
+				t.Errorf("Expected protocol error = %v, got %v", tt.protocol, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// errUnbalancedQuotes is returned by SplitArgs when a quoted argument is not closed properly.
+var errUnbalancedQuotes = errors.New("unbalanced quotes in request")
+
+// SplitArgs splits a line into arguments the same way redis-cli and inline commands do.
+// Arguments are separated by whitespace and may be quoted:
+//   - "double quoted" arguments support \n, \r, \t, \b, \a, \\, \" and \xHH escapes
+//   - 'single quoted' arguments only support the \' escape
+//
+// A closing quote must be followed by whitespace or the end of the line.
+// Example: `SET "my key" 'it\'s'` -> ["SET", "my key", "it's"]
+func SplitArgs(line string) ([]string, error) {
+	args := []string{}
+	i := 0
+
+	for {
+		// Skip blanks between arguments
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+
+		var current strings.Builder
+		inDoubleQuotes := false
+		inSingleQuotes := false
+		done := false
+
+		for !done {
+			if inDoubleQuotes {
+				if i >= len(line) {
+					return nil, errUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					b, _ := strconv.ParseUint(line[i+2:i+4], 16, 8)
+					current.WriteByte(byte(b))
+					i += 3
+				} else if c == '\\' && i+1 < len(line) {
+					i++
+					current.WriteByte(unescape(line[i]))
+				} else if c == '"' {
+					// The closing quote must be followed by a space or nothing at all
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, errUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current.WriteByte(c)
+				}
+			} else if inSingleQuotes {
+				if i >= len(line) {
+					return nil, errUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					current.WriteByte('\'')
+				} else if c == '\'' {
+					// The closing quote must be followed by a space or nothing at all
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, errUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current.WriteByte(c)
+				}
+			} else {
+				if i >= len(line) {
+					break
+				}
+				switch c := line[i]; {
+				case isSpace(c):
+					done = true
+				case c == '"':
+					inDoubleQuotes = true
+				case c == '\'':
+					inSingleQuotes = true
+				default:
+					current.WriteByte(c)
+				}
+			}
+			i++
+		}
+
+		args = append(args, current.String())
+	}
+}
+
+// unescape returns the byte represented by the escape sequence "\c" inside double quotes.
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	default:
+		return c
+	}
+}
+
+// isSpace reports whether c separates arguments.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\v' || c == '\f'
+}
+
+// isHexDigit reports whether c is a hexadecimal digit.
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		hasError bool
+	}{
+		{
+			name:     "Single word",
+			input:    "PING",
+			expected: []string{"PING"},
+		},
+		{
+			name:     "Multiple words with extra spaces",
+			input:    "  SET   key\tvalue  ",
+			expected: []string{"SET", "key", "value"},
+		},
+		{
+			name:     "Empty line",
+			input:    "",
+			expected: []string{},
+		},
+		{
+			name:     "Double quoted argument with spaces",
+			input:    `SET key "hello world"`,
+			expected: []string{"SET", "key", "hello world"},
+		},
+		{
+			name:     "Double quoted escapes",
+			input:    `ECHO "a\r\nb\t\"c\"\\"`,
+			expected: []string{"ECHO", "a\r\nb\t\"c\"\\"},
+		},
+		{
+			name:     "Hex escape",
+			input:    `ECHO "\x00\x41\xff"`,
+			expected: []string{"ECHO", "\x00A\xff"},
+		},
+		{
+			name:     "Single quoted argument",
+			input:    `SET key 'it\'s "raw" \n'`,
+			expected: []string{"SET", "key", `it's "raw" \n`},
+		},
+		{
+			name:     "Empty quoted argument",
+			input:    `SET key ""`,
+			expected: []string{"SET", "key", ""},
+		},
+		{
+			name:     "Unterminated double quotes",
+			input:    `SET key "value`,
+			hasError: true,
+		},
+		{
+			name:     "Unterminated single quotes",
+			input:    `SET key 'value`,
+			hasError: true,
+		},
+		{
+			name:     "Closing quote followed by a character",
+			input:    `SET key "value"x`,
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SplitArgs(tt.input)
+
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("Expected error but got %q", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
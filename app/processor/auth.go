@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"crypto/subtle"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Auth implements the AUTH command, authenticating the connection as the default user.
+// Example: AUTH secret
+// Example: AUTH default secret
+func (p *Processor) Auth(c *client.Client, w *resp.Writer, args []string) {
+	if len(args) > 3 {
+		w.WriteError("ERR syntax error")
+		return
+	}
+
+	username, password := "default", args[1]
+	if len(args) == 3 {
+		username, password = args[1], args[2]
+	} else if p.Config.Current().RequirePass == "" {
+		w.WriteError("ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+		return
+	}
+
+	if err := p.authenticate(username, password); err != "" {
+		w.WriteError(err)
+		return
+	}
+	c.Lock()
+	c.Authenticated = true
+	c.Unlock()
+	w.WriteSimpleString("OK")
+}
+
+// NewClient creates the client of a new connection.
+// Clients are authenticated right away when no password is required.
+func (p *Processor) NewClient() *client.Client {
+	c := client.New()
+	c.Authenticated = p.Config.Current().RequirePass == ""
+	c.CreatedAt = p.clock.Now()
+	c.LastInteraction = c.CreatedAt
+	return c
+}
+
+// authenticate checks a username and password pair and returns the error
+// to reply with when they are rejected, or an empty string when they are accepted.
+// Only the default user exists, it accepts any password unless requirepass is set.
+func (p *Processor) authenticate(username, password string) string {
+	if username != "default" {
+		return "WRONGPASS invalid username-password pair or user is disabled."
+	}
+
+	required := p.Config.Current().RequirePass
+	if required != "" && subtle.ConstantTimeCompare([]byte(password), []byte(required)) != 1 {
+		return "WRONGPASS invalid username-password pair or user is disabled."
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+func TestAuthWithoutPassword(t *testing.T) {
+	processor := NewProcessor()
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "AUTH password without requirepass",
+			input:    []string{"AUTH", "secret"},
+			expected: "-ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?\r\n",
+		},
+		{
+			name:     "AUTH default user accepts any password",
+			input:    []string{"AUTH", "default", "anything"},
+			expected: "+OK\r\n",
+		},
+		{
+			name:     "AUTH unknown user",
+			input:    []string{"AUTH", "alice", "secret"},
+			expected: "-WRONGPASS invalid username-password pair or user is disabled.\r\n",
+		},
+		{
+			name:     "AUTH with too many arguments",
+			input:    []string{"AUTH", "a", "b", "c"},
+			expected: "-ERR syntax error\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequirePass(t *testing.T) {
+	cfg := config.Default()
+	cfg.RequirePass = "secret"
+	processor := NewProcessorWithOptions(Options{Config: cfg})
+	c := processor.NewClient()
+
+	steps := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"GET", "key"}, "-NOAUTH Authentication required.\r\n"},
+		{[]string{"PING"}, "-NOAUTH Authentication required.\r\n"},
+		{[]string{"GET"}, "-ERR wrong number of arguments for 'get' command\r\n"},
+		{[]string{"HELLO", "3"}, "-NOAUTH HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time\r\n"},
+		{[]string{"AUTH", "wrong"}, "-WRONGPASS invalid username-password pair or user is disabled.\r\n"},
+		{[]string{"AUTH", "default", "wrong"}, "-WRONGPASS invalid username-password pair or user is disabled.\r\n"},
+		{[]string{"GET", "key"}, "-NOAUTH Authentication required.\r\n"},
+		{[]string{"AUTH", "secret"}, "+OK\r\n"},
+		{[]string{"GET", "key"}, "$-1\r\n"},
+	}
+
+	for _, step := range steps {
+		result := execute(processor, c, step.input)
+		if result != step.expected {
+			t.Fatalf("Execute(%v) = %q, want %q", step.input, result, step.expected)
+		}
+	}
+}
+
+func TestHelloAuthenticates(t *testing.T) {
+	cfg := config.Default()
+	cfg.RequirePass = "secret"
+	processor := NewProcessorWithOptions(Options{Config: cfg})
+	c := processor.NewClient()
+
+	result := execute(processor, c, []string{"HELLO", "3", "AUTH", "default", "wrong"})
+	if result != "-WRONGPASS invalid username-password pair or user is disabled.\r\n" {
+		t.Fatalf("Unexpected reply to a wrong password: %q", result)
+	}
+	if c.Authenticated || c.Protocol != 2 {
+		t.Fatal("A failed HELLO must not change the client")
+	}
+
+	execute(processor, c, []string{"HELLO", "3", "AUTH", "default", "secret"})
+	if !c.Authenticated || c.Protocol != 3 {
+		t.Fatal("Expected HELLO to authenticate the client and switch to RESP3")
+	}
+	if result := execute(processor, c, []string{"PING"}); result != "+PONG\r\n" {
+		t.Errorf("PING = %q", result)
+	}
+}
@@ -0,0 +1,482 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/logging"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// clientTypes lists the client types accepted by CLIENT LIST TYPE and CLIENT KILL TYPE.
+// Only normal clients exist as replication and pub/sub are not supported.
+var clientTypes = []string{"normal", "master", "replica", "slave", "pubsub"}
+
+// errUnblocked is the error replied by blocking commands interrupted by CLIENT UNBLOCK ERROR
+var errUnblocked = errors.New("UNBLOCKED client unblocked via CLIENT UNBLOCK")
+
+// ErrMaxClients is returned by AddClient when maxclients clients are already connected,
+// its message is the error replied to the refused connection
+var ErrMaxClients = errors.New("ERR max number of clients reached")
+
+// IdleCheckInterval is how often StartIdleTimeout looks for idle clients
+const IdleCheckInterval = time.Second
+
+// clientRegistry holds the clients connected to the server.
+type clientRegistry struct {
+	mutex sync.RWMutex
+	// clients maps client ids to connected clients
+	clients map[int64]*client.Client
+}
+
+// AddClient records that c connected to the server.
+// It returns ErrMaxClients, and counts the connection as rejected, once maxclients clients are connected.
+func (p *Processor) AddClient(c *client.Client) error {
+	p.clients.mutex.Lock()
+	if len(p.clients.clients) >= p.Config.Current().MaxClients {
+		p.clients.mutex.Unlock()
+		p.RejectConnection()
+		return ErrMaxClients
+	}
+	if p.clients.clients == nil {
+		p.clients.clients = make(map[int64]*client.Client)
+	}
+	p.clients.clients[c.ID] = c
+	p.clients.mutex.Unlock()
+
+	p.stats.connectedClients.Add(1)
+	p.stats.connectionsReceived.Add(1)
+	return nil
+}
+
+// RemoveClient records that c disconnected from the server.
+func (p *Processor) RemoveClient(c *client.Client) {
+	p.clients.mutex.Lock()
+	delete(p.clients.clients, c.ID)
+	p.clients.mutex.Unlock()
+
+	p.stats.connectedClients.Add(-1)
+}
+
+// Clients returns the connected clients ordered by id.
+func (p *Processor) Clients() []*client.Client {
+	p.clients.mutex.RLock()
+	clients := make([]*client.Client, 0, len(p.clients.clients))
+	for _, c := range p.clients.clients {
+		clients = append(clients, c)
+	}
+	p.clients.mutex.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// CloseIdleClients kills the clients that sent no command for the last timeout seconds and
+// returns how many were killed. Blocked and monitoring clients are legitimately quiet and kept.
+func (p *Processor) CloseIdleClients() int {
+	timeout := p.Config.Current().Timeout
+	if timeout <= 0 {
+		return 0
+	}
+
+	now := p.clock.Now()
+	closed := 0
+	for _, c := range p.Clients() {
+		s := c.Snapshot()
+		if s.Blocked || s.Monitor || now.Sub(s.LastInteraction) < time.Duration(timeout)*time.Second {
+			continue
+		}
+		logging.Verbose(p.Logger, "Closing idle client", "client_id", s.ID, "addr", s.Addr)
+		c.Kill()
+		closed++
+	}
+	return closed
+}
+
+// StartIdleTimeout runs CloseIdleClients every interval in a background goroutine.
+// The returned function stops it.
+func (p *Processor) StartIdleTimeout(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.CloseIdleClients()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// clientInfo formats a line of CLIENT LIST describing the snapshot s of a client.
+// Fields the server has no equivalent for, such as pub/sub subscriptions, are reported as empty.
+// Example: id=3 addr=127.0.0.1:50000 laddr=127.0.0.1:6379 name=worker age=5 idle=0 flags=N db=0 ...
+func clientInfo(s client.Info, now time.Time) string {
+	flags := ""
+	if s.Monitor {
+		flags += "O"
+	}
+	if s.Blocked {
+		flags += "b"
+	}
+	if s.CloseAfterReply {
+		flags += "c"
+	}
+	if s.UnixSocket {
+		flags += "U"
+	}
+	if flags == "" {
+		flags = "N"
+	}
+
+	age := int64(now.Sub(s.CreatedAt) / time.Second)
+	idle := int64(now.Sub(s.LastInteraction) / time.Second)
+	return fmt.Sprintf("id=%d addr=%s laddr=%s name=%s age=%d idle=%d flags=%s db=0 sub=0 psub=0 ssub=0 "+
+		"multi=-1 watch=0 qbuf=%d obl=%d oll=0 omem=0 cmd=%s user=default resp=%d",
+		s.ID, s.Addr, s.LocalAddr, s.Name, age, idle, flags,
+		s.QueryBuffer, s.OutputBuffer, s.LastCommand, s.Protocol)
+}
+
+// validClientType reports whether t names a known client type, case insensitively.
+func validClientType(t string) bool {
+	for _, known := range clientTypes {
+		if strings.EqualFold(t, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientFilter selects the clients affected by CLIENT KILL.
+type clientFilter struct {
+	// ids are the accepted client ids, nil to accept any
+	ids []int64
+	// addr, laddr and user are the accepted values, empty to accept any
+	addr, laddr, user string
+	// clientType is the accepted type, empty to accept any
+	clientType string
+	// maxAge only accepts clients connected for longer than this many seconds, 0 to accept any
+	maxAge int64
+	// skipMe excludes the calling client
+	skipMe bool
+}
+
+// matches reports whether the snapshot s of a client is accepted by f.
+func (f *clientFilter) matches(s client.Info, caller *client.Client, now time.Time) bool {
+	if f.skipMe && s.ID == caller.ID {
+		return false
+	}
+	if f.ids != nil {
+		found := false
+		for _, id := range f.ids {
+			found = found || id == s.ID
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.addr != "" && f.addr != s.Addr {
+		return false
+	}
+	if f.laddr != "" && f.laddr != s.LocalAddr {
+		return false
+	}
+	if f.user != "" && f.user != "default" {
+		return false
+	}
+	if f.clientType != "" && !strings.EqualFold(f.clientType, "normal") {
+		return false
+	}
+	if f.maxAge > 0 && int64(now.Sub(s.CreatedAt)/time.Second) < f.maxAge {
+		return false
+	}
+	return true
+}
+
+// parseClientKillFilter parses the "<filter> <value> ..." arguments of CLIENT KILL
+// and returns the error to reply with when they are invalid.
+func parseClientKillFilter(args []string) (*clientFilter, string) {
+	f := &clientFilter{skipMe: true}
+	if len(args)%2 != 0 {
+		return nil, "ERR syntax error"
+	}
+	for i := 0; i < len(args); i += 2 {
+		value := args[i+1]
+		switch strings.ToUpper(args[i]) {
+		case "ID":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, "ERR client-id should be greater than 0"
+			}
+			f.ids = append(f.ids, id)
+		case "ADDR":
+			f.addr = value
+		case "LADDR":
+			f.laddr = value
+		case "USER":
+			if value != "default" {
+				return nil, "ERR No such user '" + resp.ErrorArg(value) + "'"
+			}
+			f.user = value
+		case "TYPE":
+			if !validClientType(value) {
+				return nil, "ERR Unknown client type '" + resp.ErrorArg(value) + "'"
+			}
+			f.clientType = value
+		case "SKIPME":
+			switch strings.ToLower(value) {
+			case "yes":
+				f.skipMe = true
+			case "no":
+				f.skipMe = false
+			default:
+				return nil, "ERR syntax error"
+			}
+		case "MAXAGE":
+			age, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || age < 0 {
+				return nil, "ERR syntax error"
+			}
+			f.maxAge = age
+		default:
+			return nil, "ERR syntax error"
+		}
+	}
+	return f, ""
+}
+
+// killClients kills the clients accepted by f and returns how many were killed.
+// The calling client is only closed once its reply is written.
+func (p *Processor) killClients(caller *client.Client, f *clientFilter) int {
+	now := p.clock.Now()
+	killed := 0
+	for _, target := range p.Clients() {
+		if !f.matches(target.Snapshot(), caller, now) {
+			continue
+		}
+		if target == caller {
+			caller.Lock()
+			caller.CloseAfterReply = true
+			caller.Unlock()
+		} else {
+			target.Kill()
+		}
+		killed++
+	}
+	return killed
+}
+
+// ClientCommand implements the CLIENT command, inspecting and managing client connections.
+// Example: CLIENT LIST
+// Example: CLIENT SETNAME worker-1
+// Example: CLIENT KILL ID 42
+// Example: CLIENT UNBLOCK 42 ERROR
+// Example: CLIENT PAUSE 5000 WRITE
+func (p *Processor) ClientCommand(c *client.Client, w *resp.Writer, args []string) {
+	subcommand := strings.ToUpper(args[1])
+	switch subcommand {
+	case "ID":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("client|id"))
+			return
+		}
+		w.WriteInteger(c.ID)
+	case "GETNAME":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("client|getname"))
+			return
+		}
+		if c.Name == "" {
+			w.WriteNullBulk()
+			return
+		}
+		w.WriteBulk(c.Name)
+	case "SETNAME":
+		if len(args) != 3 {
+			w.WriteError(wrongArityError("client|setname"))
+			return
+		}
+		if !client.ValidName(args[2]) {
+			w.WriteError("ERR Client names cannot contain spaces, newlines or special characters.")
+			return
+		}
+		c.Lock()
+		c.Name = args[2]
+		c.Unlock()
+		w.WriteSimpleString("OK")
+	case "INFO":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("client|info"))
+			return
+		}
+		w.WriteVerbatim("txt", clientInfo(c.Snapshot(), p.clock.Now())+"\n")
+	case "LIST":
+		p.clientList(w, args)
+	case "KILL":
+		if len(args) < 3 {
+			w.WriteError(wrongArityError("client|kill"))
+			return
+		}
+		if len(args) == 3 {
+			// Old form: CLIENT KILL addr:port
+			if p.killClients(c, &clientFilter{addr: args[2]}) == 0 {
+				w.WriteError("ERR No such client")
+				return
+			}
+			w.WriteSimpleString("OK")
+			return
+		}
+		f, errMsg := parseClientKillFilter(args[2:])
+		if errMsg != "" {
+			w.WriteError(errMsg)
+			return
+		}
+		w.WriteInteger(int64(p.killClients(c, f)))
+	case "UNBLOCK":
+		p.clientUnblock(w, args)
+	case "PAUSE":
+		p.clientPause(w, args)
+	case "UNPAUSE":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("client|unpause"))
+			return
+		}
+		p.Unpause()
+		w.WriteSimpleString("OK")
+	default:
+		w.WriteError("ERR unknown subcommand '" + resp.ErrorArg(args[1]) + "'. Try CLIENT HELP.")
+	}
+}
+
+// clientList implements CLIENT LIST [TYPE type] [ID id ...].
+func (p *Processor) clientList(w *resp.Writer, args []string) {
+	var ids []int64
+	clientType := ""
+	if len(args) > 2 {
+		switch strings.ToUpper(args[2]) {
+		case "TYPE":
+			if len(args) != 4 {
+				w.WriteError("ERR syntax error")
+				return
+			}
+			if !validClientType(args[3]) {
+				w.WriteError("ERR Unknown client type '" + resp.ErrorArg(args[3]) + "'")
+				return
+			}
+			clientType = args[3]
+		case "ID":
+			if len(args) < 4 {
+				w.WriteError("ERR syntax error")
+				return
+			}
+			for _, arg := range args[3:] {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil || id <= 0 {
+					w.WriteError("ERR Invalid client ID")
+					return
+				}
+				ids = append(ids, id)
+			}
+		default:
+			w.WriteError("ERR syntax error")
+			return
+		}
+	}
+
+	f := &clientFilter{ids: ids, clientType: clientType}
+	now := p.clock.Now()
+	var sb strings.Builder
+	for _, target := range p.Clients() {
+		s := target.Snapshot()
+		if f.matches(s, nil, now) {
+			sb.WriteString(clientInfo(s, now))
+			sb.WriteByte('\n')
+		}
+	}
+	w.WriteVerbatim("txt", sb.String())
+}
+
+// clientUnblock implements CLIENT UNBLOCK id [TIMEOUT|ERROR], interrupting the blocking command
+// a client waits in. TIMEOUT, the default, replies as if the command timed out.
+func (p *Processor) clientUnblock(w *resp.Writer, args []string) {
+	if len(args) != 3 && len(args) != 4 {
+		w.WriteError(wrongArityError("client|unblock"))
+		return
+	}
+	id, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		w.WriteError("ERR value is not an integer or out of range")
+		return
+	}
+	cause := context.DeadlineExceeded
+	if len(args) == 4 {
+		switch strings.ToUpper(args[3]) {
+		case "TIMEOUT":
+		case "ERROR":
+			cause = errUnblocked
+		default:
+			w.WriteError("ERR CLIENT UNBLOCK reason should be TIMEOUT or ERROR")
+			return
+		}
+	}
+
+	p.clients.mutex.RLock()
+	target := p.clients.clients[id]
+	p.clients.mutex.RUnlock()
+	if target == nil || !target.Unblock(cause) {
+		w.WriteInteger(0)
+		return
+	}
+	w.WriteInteger(1)
+}
+
+// clientPause implements CLIENT PAUSE timeout [WRITE|ALL], holding back commands for timeout milliseconds.
+func (p *Processor) clientPause(w *resp.Writer, args []string) {
+	if len(args) != 3 && len(args) != 4 {
+		w.WriteError(wrongArityError("client|pause"))
+		return
+	}
+	ms, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		w.WriteError("ERR timeout is not an integer or out of range")
+		return
+	}
+	if ms < 0 {
+		w.WriteError("ERR timeout is negative")
+		return
+	}
+	if ms > int64(math.MaxInt64/time.Millisecond) {
+		w.WriteError("ERR timeout is out of range")
+		return
+	}
+	mode := pauseAll
+	if len(args) == 4 {
+		switch strings.ToUpper(args[3]) {
+		case "ALL":
+		case "WRITE":
+			mode = pauseWrite
+		default:
+			w.WriteError("ERR syntax error")
+			return
+		}
+	}
+
+	p.Pause(mode, p.clock.Now().Add(time.Duration(ms)*time.Millisecond))
+	w.WriteSimpleString("OK")
+}
@@ -0,0 +1,462 @@
+package processor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// connect registers a new client as if it connected from addr, recording when it gets killed.
+func connect(p *Processor, addr string, killed *bool) *client.Client {
+	c := p.NewClient()
+	c.Addr = addr
+	c.LocalAddr = "127.0.0.1:6379"
+	c.SetCloser(func() error {
+		*killed = true
+		return nil
+	})
+	p.AddClient(c)
+	return c
+}
+
+// bulkBody returns the payload of a RESP2 bulk string reply.
+func bulkBody(t *testing.T, reply string) string {
+	t.Helper()
+	header, body, ok := strings.Cut(reply, "\r\n")
+	if !ok || !strings.HasPrefix(header, "$") {
+		t.Fatalf("Expected a bulk string, got %q", reply)
+	}
+	return strings.TrimSuffix(body, "\r\n")
+}
+
+func TestClientIDAndName(t *testing.T) {
+	p := NewProcessor()
+	c := p.NewClient()
+
+	steps := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"CLIENT", "ID"}, ":" + strconv.FormatInt(c.ID, 10) + "\r\n"},
+		{[]string{"CLIENT", "GETNAME"}, "$-1\r\n"},
+		{[]string{"CLIENT", "SETNAME", "worker-1"}, "+OK\r\n"},
+		{[]string{"CLIENT", "GETNAME"}, "$8\r\nworker-1\r\n"},
+		{[]string{"CLIENT", "SETNAME", "two words"}, "-ERR Client names cannot contain spaces, newlines or special characters.\r\n"},
+		{[]string{"CLIENT", "SETNAME", ""}, "+OK\r\n"},
+		{[]string{"CLIENT", "GETNAME"}, "$-1\r\n"},
+		{[]string{"CLIENT", "ID", "extra"}, "-ERR wrong number of arguments for 'client|id' command\r\n"},
+		{[]string{"CLIENT", "NOPE"}, "-ERR unknown subcommand 'NOPE'. Try CLIENT HELP.\r\n"},
+	}
+
+	for _, step := range steps {
+		if got := execute(p, c, step.input); got != step.expected {
+			t.Errorf("%v: expected %q, got %q", step.input, step.expected, got)
+		}
+	}
+}
+
+func TestClientList(t *testing.T) {
+	clk := clock.NewManual(time.Unix(1700000000, 0))
+	p := NewProcessorWithOptions(Options{Clock: clk})
+	var killed bool
+	first := connect(p, "127.0.0.1:50000", &killed)
+	second := connect(p, "127.0.0.1:50001", &killed)
+
+	execute(p, first, []string{"CLIENT", "SETNAME", "worker"})
+	clk.Advance(3 * time.Second)
+	execute(p, second, []string{"PING"})
+
+	list := bulkBody(t, execute(p, second, []string{"CLIENT", "LIST"}))
+	lines := strings.Split(strings.TrimSuffix(list, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 clients, got %q", list)
+	}
+	want := "id=" + strconv.FormatInt(first.ID, 10) + " addr=127.0.0.1:50000 laddr=127.0.0.1:6379 name=worker age=3 idle=3 flags=N db=0 " +
+		"sub=0 psub=0 ssub=0 multi=-1 watch=0 qbuf=0 obl=0 oll=0 omem=0 cmd=client user=default resp=2"
+	if lines[0] != want {
+		t.Errorf("Expected %q, got %q", want, lines[0])
+	}
+	if !strings.Contains(lines[1], "addr=127.0.0.1:50001") || !strings.Contains(lines[1], "cmd=client") {
+		t.Errorf("Unexpected second line %q", lines[1])
+	}
+
+	only := bulkBody(t, execute(p, second, []string{"CLIENT", "LIST", "ID", strconv.FormatInt(second.ID, 10)}))
+	if strings.Count(only, "\n") != 1 || !strings.Contains(only, "addr=127.0.0.1:50001") {
+		t.Errorf("Expected only the second client, got %q", only)
+	}
+	if got := bulkBody(t, execute(p, second, []string{"CLIENT", "LIST", "TYPE", "pubsub"})); got != "" {
+		t.Errorf("Expected no pub/sub client, got %q", got)
+	}
+
+	errors := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"CLIENT", "LIST", "ID", "abc"}, "-ERR Invalid client ID\r\n"},
+		{[]string{"CLIENT", "LIST", "TYPE", "nope"}, "-ERR Unknown client type 'nope'\r\n"},
+		{[]string{"CLIENT", "LIST", "FOO"}, "-ERR syntax error\r\n"},
+	}
+	for _, tt := range errors {
+		if got := execute(p, second, tt.input); got != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	p.RemoveClient(first)
+	if got := bulkBody(t, execute(p, second, []string{"CLIENT", "LIST"})); strings.Contains(got, "name=worker") {
+		t.Errorf("Expected disconnected clients to be unlisted, got %q", got)
+	}
+}
+
+func TestClientInfo(t *testing.T) {
+	p := NewProcessor()
+	var killed bool
+	c := connect(p, "127.0.0.1:50000", &killed)
+	execute(p, c, []string{"HELLO", "3"})
+
+	got := execute(p, c, []string{"CLIENT", "INFO"})
+	if !strings.HasPrefix(got, "=") || !strings.Contains(got, "addr=127.0.0.1:50000") || !strings.Contains(got, "resp=3") {
+		t.Errorf("Unexpected CLIENT INFO reply %q", got)
+	}
+}
+
+func TestClientKill(t *testing.T) {
+	p := NewProcessor()
+	var callerKilled, firstKilled, secondKilled bool
+	caller := connect(p, "127.0.0.1:50000", &callerKilled)
+	first := connect(p, "127.0.0.1:50001", &firstKilled)
+	second := connect(p, "127.0.0.1:50002", &secondKilled)
+
+	steps := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"CLIENT", "KILL", "127.0.0.1:59999"}, "-ERR No such client\r\n"},
+		{[]string{"CLIENT", "KILL", "127.0.0.1:50001"}, "+OK\r\n"},
+		{[]string{"CLIENT", "KILL", "ID", "0"}, "-ERR client-id should be greater than 0\r\n"},
+		{[]string{"CLIENT", "KILL", "USER", "alice"}, "-ERR No such user 'alice'\r\n"},
+		{[]string{"CLIENT", "KILL", "TYPE", "nope"}, "-ERR Unknown client type 'nope'\r\n"},
+		{[]string{"CLIENT", "KILL", "ID", "1", "TYPE"}, "-ERR syntax error\r\n"},
+		{[]string{"CLIENT", "KILL", "SKIPME", "maybe"}, "-ERR syntax error\r\n"},
+		{[]string{"CLIENT", "KILL", "TYPE", "pubsub"}, ":0\r\n"},
+		{[]string{"CLIENT", "KILL", "ID", strconv.FormatInt(caller.ID, 10)}, ":0\r\n"},
+		{[]string{"CLIENT", "KILL", "ID", strconv.FormatInt(second.ID, 10)}, ":1\r\n"},
+	}
+	for _, step := range steps {
+		if got := execute(p, caller, step.input); got != step.expected {
+			t.Errorf("%v: expected %q, got %q", step.input, step.expected, got)
+		}
+	}
+
+	if !firstKilled || !first.Killed() {
+		t.Error("Expected the client killed by address to be closed")
+	}
+	if !secondKilled || !second.Killed() {
+		t.Error("Expected the client killed by id to be closed")
+	}
+	if callerKilled || caller.CloseAfterReply {
+		t.Error("Expected SKIPME to spare the caller by default")
+	}
+
+	if got := execute(p, caller, []string{"CLIENT", "KILL", "USER", "default", "SKIPME", "no"}); got != ":3\r\n" {
+		t.Errorf("Expected every client to be killed, got %q", got)
+	}
+	if callerKilled || !caller.CloseAfterReply {
+		t.Error("Expected the caller to be closed only once its reply is written")
+	}
+}
+
+// waitBlocked waits until c is reported as blocked, failing after a second.
+func waitBlocked(t *testing.T, c *client.Client) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !c.Snapshot().Blocked {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the client to block")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// receive returns the next reply sent on replies, failing after a second.
+func receive(t *testing.T, replies <-chan string) string {
+	t.Helper()
+	select {
+	case reply := <-replies:
+		return reply
+	case <-time.After(time.Second):
+		t.Fatal("Expected a reply")
+		return ""
+	}
+}
+
+func TestClientUnblock(t *testing.T) {
+	p := NewProcessor()
+	var killed bool
+	caller := connect(p, "127.0.0.1:50000", &killed)
+	blocked := connect(p, "127.0.0.1:50001", &killed)
+	id := strconv.FormatInt(blocked.ID, 10)
+
+	steps := []struct {
+		reason   []string
+		expected string
+	}{
+		{nil, "*-1\r\n"},
+		{[]string{"TIMEOUT"}, "*-1\r\n"},
+		{[]string{"error"}, "-UNBLOCKED client unblocked via CLIENT UNBLOCK\r\n"},
+	}
+	for _, step := range steps {
+		replies := make(chan string, 1)
+		go func() { replies <- execute(p, blocked, []string{"BLPOP", "list", "0"}) }()
+		waitBlocked(t, blocked)
+
+		if got := execute(p, caller, append([]string{"CLIENT", "UNBLOCK", id}, step.reason...)); got != ":1\r\n" {
+			t.Errorf("CLIENT UNBLOCK %v: expected :1, got %q", step.reason, got)
+		}
+		if got := receive(t, replies); got != step.expected {
+			t.Errorf("CLIENT UNBLOCK %v: expected BLPOP to reply %q, got %q", step.reason, step.expected, got)
+		}
+	}
+
+	if blocked.Snapshot().Blocked || p.ListStore.BlockedClients() != 0 || p.ListStore.BlockingKeys() != 0 {
+		t.Error("Expected unblocked clients to stop waiting on their keys")
+	}
+	// The list is usable again once the waiting clients are gone
+	execute(p, caller, []string{"RPUSH", "list", "a"})
+	if got := execute(p, caller, []string{"LLEN", "list"}); got != ":1\r\n" {
+		t.Errorf("Expected the pushed element to stay in the list, got %q", got)
+	}
+
+	errors := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"CLIENT", "UNBLOCK", id}, ":0\r\n"},
+		{[]string{"CLIENT", "UNBLOCK", "999999"}, ":0\r\n"},
+		{[]string{"CLIENT", "UNBLOCK", "abc"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"CLIENT", "UNBLOCK", id, "NOW"}, "-ERR CLIENT UNBLOCK reason should be TIMEOUT or ERROR\r\n"},
+	}
+	for _, tt := range errors {
+		if got := execute(p, caller, tt.input); got != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestClientServedRightAwayIsNotBlocked(t *testing.T) {
+	p := NewProcessor()
+	var killed bool
+	caller := connect(p, "127.0.0.1:50000", &killed)
+	c := connect(p, "127.0.0.1:50001", &killed)
+	execute(p, caller, []string{"RPUSH", "list", "a"})
+
+	// Observe the client while BLPOP runs, before it finds the element
+	cmd, _ := p.LookupCommand("blpop")
+	blpop := cmd.BlockingHandler
+	var flags, unblocked string
+	cmd.BlockingHandler = func(block func() context.Context, w *resp.Writer, args []string) {
+		flags = clientInfo(c.Snapshot(), time.Now())
+		unblocked = execute(p, caller, []string{"CLIENT", "UNBLOCK", strconv.FormatInt(c.ID, 10)})
+		blpop(block, w, args)
+	}
+
+	if got := execute(p, c, []string{"BLPOP", "list", "0"}); got != "*2\r\n$4\r\nlist\r\n$1\r\na\r\n" {
+		t.Errorf("Expected BLPOP to pop the element, got %q", got)
+	}
+	if !strings.Contains(flags, " flags=N ") {
+		t.Errorf("Expected the client not to be flagged as blocked, got %q", flags)
+	}
+	if unblocked != ":0\r\n" {
+		t.Errorf("Expected CLIENT UNBLOCK to find no blocked client, got %q", unblocked)
+	}
+}
+
+func TestClientKillWakesBlockedClient(t *testing.T) {
+	p := NewProcessor()
+	var callerKilled, blockedKilled bool
+	caller := connect(p, "127.0.0.1:50000", &callerKilled)
+	blocked := connect(p, "127.0.0.1:50001", &blockedKilled)
+
+	replies := make(chan string, 1)
+	go func() { replies <- execute(p, blocked, []string{"BLPOP", "list", "0"}) }()
+	waitBlocked(t, blocked)
+
+	execute(p, caller, []string{"CLIENT", "KILL", "ID", strconv.FormatInt(blocked.ID, 10)})
+	if got := receive(t, replies); got != "-ERR client killed\r\n" {
+		t.Errorf("Expected the killed client to stop waiting, got %q", got)
+	}
+}
+
+func TestClientPause(t *testing.T) {
+	p, clk := newManualProcessor(t)
+	var killed bool
+	admin := connect(p, "127.0.0.1:50000", &killed)
+	writer := connect(p, "127.0.0.1:50001", &killed)
+
+	if got := execute(p, admin, []string{"CLIENT", "PAUSE", "1000", "WRITE"}); got != "+OK\r\n" {
+		t.Fatalf("Expected CLIENT PAUSE to succeed, got %q", got)
+	}
+
+	// Reads keep running while writes are held
+	if got := execute(p, admin, []string{"GET", "key"}); got != "$-1\r\n" {
+		t.Errorf("Expected GET to run during a WRITE pause, got %q", got)
+	}
+	replies := make(chan string, 1)
+	go func() { replies <- execute(p, writer, []string{"SET", "key", "value"}) }()
+	waitBlocked(t, writer)
+
+	clk.Advance(999 * time.Millisecond)
+	select {
+	case reply := <-replies:
+		t.Fatalf("Expected SET to be held until the pause ends, got %q", reply)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clk.Advance(time.Millisecond)
+	if got := receive(t, replies); got != "+OK\r\n" {
+		t.Errorf("Expected SET to run once the pause ended, got %q", got)
+	}
+
+	// ALL holds every command until CLIENT UNPAUSE
+	execute(p, admin, []string{"CLIENT", "PAUSE", "60000"})
+	go func() { replies <- execute(p, writer, []string{"GET", "key"}) }()
+	waitBlocked(t, writer)
+	if got := execute(p, admin, []string{"CLIENT", "UNPAUSE"}); got != "+OK\r\n" {
+		t.Errorf("Expected CLIENT UNPAUSE to succeed, got %q", got)
+	}
+	if got := receive(t, replies); got != "$5\r\nvalue\r\n" {
+		t.Errorf("Expected GET to run once unpaused, got %q", got)
+	}
+	if writer.Snapshot().Blocked {
+		t.Error("Expected the client to no longer be blocked")
+	}
+
+	errors := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"CLIENT", "PAUSE", "abc"}, "-ERR timeout is not an integer or out of range\r\n"},
+		{[]string{"CLIENT", "PAUSE", "-1"}, "-ERR timeout is negative\r\n"},
+		{[]string{"CLIENT", "PAUSE", "10", "READ"}, "-ERR syntax error\r\n"},
+	}
+	for _, tt := range errors {
+		if got := execute(p, admin, tt.input); got != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestKilledWhilePaused(t *testing.T) {
+	p, _ := newManualProcessor(t)
+	var adminKilled, writerKilled bool
+	admin := connect(p, "127.0.0.1:50000", &adminKilled)
+	writer := connect(p, "127.0.0.1:50001", &writerKilled)
+
+	execute(p, admin, []string{"CLIENT", "PAUSE", "60000", "WRITE"})
+	replies := make(chan string, 1)
+	go func() { replies <- execute(p, writer, []string{"SET", "key", "value"}) }()
+	waitBlocked(t, writer)
+
+	if got := execute(p, admin, []string{"CLIENT", "KILL", "ID", strconv.FormatInt(writer.ID, 10)}); got != ":1\r\n" {
+		t.Fatalf("Expected CLIENT KILL to kill the held client, got %q", got)
+	}
+	// The held command gives up right away, without waiting for the pause to end
+	if got := receive(t, replies); got != "" {
+		t.Errorf("Expected no reply for the killed client, got %q", got)
+	}
+	execute(p, admin, []string{"CLIENT", "UNPAUSE"})
+	if got := execute(p, admin, []string{"EXISTS", "key"}); got != ":0\r\n" {
+		t.Errorf("Expected the command of the killed client not to run, got %q", got)
+	}
+	if !writerKilled || writer.Snapshot().Blocked {
+		t.Error("Expected the client to be killed and no longer blocked")
+	}
+}
+
+func TestMaxClients(t *testing.T) {
+	p := NewProcessor()
+	execute(p, p.NewClient(), []string{"CONFIG", "SET", "maxclients", "2"})
+
+	for i := 0; i < 2; i++ {
+		if err := p.AddClient(p.NewClient()); err != nil {
+			t.Fatalf("Expected client %d to be accepted, got %v", i, err)
+		}
+	}
+	rejected := p.NewClient()
+	if err := p.AddClient(rejected); err != ErrMaxClients {
+		t.Fatalf("Expected ErrMaxClients, got %v", err)
+	}
+	if got := ErrMaxClients.Error(); got != "ERR max number of clients reached" {
+		t.Errorf("Unexpected error message %q", got)
+	}
+	if len(p.Clients()) != 2 || p.stats.rejectedConnections.Load() != 1 {
+		t.Errorf("Expected 2 clients and 1 rejected connection, got %d and %d", len(p.Clients()), p.stats.rejectedConnections.Load())
+	}
+
+	p.RemoveClient(p.Clients()[0])
+	if err := p.AddClient(rejected); err != nil {
+		t.Errorf("Expected a client to be accepted once another left, got %v", err)
+	}
+}
+
+func TestCloseIdleClients(t *testing.T) {
+	p, clk := newManualProcessor(t)
+	var idleKilled, activeKilled, blockedKilled, monitorKilled bool
+	idle := connect(p, "127.0.0.1:50000", &idleKilled)
+	active := connect(p, "127.0.0.1:50001", &activeKilled)
+	blocked := connect(p, "127.0.0.1:50002", &blockedKilled)
+	monitor := connect(p, "127.0.0.1:50003", &monitorKilled)
+	execute(p, monitor, []string{"MONITOR"})
+
+	// The timeout is disabled by default
+	clk.Advance(time.Hour)
+	if closed := p.CloseIdleClients(); closed != 0 {
+		t.Fatalf("Expected no client to be closed without a timeout, got %d", closed)
+	}
+
+	execute(p, idle, []string{"CONFIG", "SET", "timeout", "10"})
+	idle.LastInteraction = clk.Now()
+	active.LastInteraction = clk.Now()
+	replies := make(chan string, 1)
+	go func() { replies <- execute(p, blocked, []string{"BLPOP", "list", "0"}) }()
+	waitBlocked(t, blocked)
+
+	clk.Advance(9 * time.Second)
+	active.LastInteraction = clk.Now()
+	clk.Advance(time.Second)
+	if closed := p.CloseIdleClients(); closed != 1 {
+		t.Errorf("Expected only the idle client to be closed, got %d", closed)
+	}
+	if !idleKilled || activeKilled || blockedKilled || monitorKilled {
+		t.Errorf("Unexpected clients closed: idle=%v active=%v blocked=%v monitor=%v",
+			idleKilled, activeKilled, blockedKilled, monitorKilled)
+	}
+
+	// The blocked client stays connected and is served as usual
+	execute(p, active, []string{"RPUSH", "list", "a"})
+	if got := receive(t, replies); got != "*2\r\n$4\r\nlist\r\n$1\r\na\r\n" {
+		t.Errorf("Expected the blocked client to be served, got %q", got)
+	}
+	// Having blocked for longer than the timeout doesn't make it idle once served
+	p.RemoveClient(idle)
+	if closed := p.CloseIdleClients(); closed != 0 || blockedKilled {
+		t.Errorf("Expected the client served after blocking to stay connected, got %d closed", closed)
+	}
+}
+
+func TestJumpTimeKeepsIdleClients(t *testing.T) {
+	p := newDebugProcessor(nil)
+	var killed bool
+	c := connect(p, "127.0.0.1:50000", &killed)
+	execute(p, c, []string{"CONFIG", "SET", "timeout", "10"})
+
+	execute(p, c, []string{"DEBUG", "JUMP-TIME", strconv.Itoa(int(time.Hour / time.Millisecond))})
+	if closed := p.CloseIdleClients(); closed != 0 || killed {
+		t.Errorf("Expected moving the keyspace clock to keep clients connected, got %d closed", closed)
+	}
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+)
+
+func newManualProcessor(t *testing.T) (*Processor, *clock.Manual) {
+	t.Helper()
+	c := clock.NewManual(time.UnixMilli(1700000000000))
+	return NewProcessorWithOptions(Options{Clock: c}), c
+}
+
+func TestClockDrivesExpiry(t *testing.T) {
+	processor, c := newManualProcessor(t)
+	processor.ProcessCommand([]string{"SET", "k", "v", "PX", "100"})
+
+	c.Advance(100 * time.Millisecond)
+	if result := processor.ProcessCommand([]string{"GET", "k"}); result != "$1\r\nv\r\n" {
+		t.Errorf("GET at the expiry time = %q, want the value", result)
+	}
+
+	c.Advance(time.Millisecond)
+	if result := processor.ProcessCommand([]string{"GET", "k"}); result != "$-1\r\n" {
+		t.Errorf("GET after the expiry time = %q, want null", result)
+	}
+}
+
+func TestClockDrivesXADDIDs(t *testing.T) {
+	processor, c := newManualProcessor(t)
+
+	tests := []struct {
+		advance  time.Duration
+		expected string
+	}{
+		{0, "$15\r\n1700000000000-0\r\n"},
+		{0, "$15\r\n1700000000000-1\r\n"},
+		{5 * time.Millisecond, "$15\r\n1700000000005-0\r\n"},
+		// The clock going backwards must not produce a smaller ID
+		{-time.Second, "$15\r\n1700000000005-1\r\n"},
+	}
+
+	for _, tt := range tests {
+		c.Advance(tt.advance)
+		result := processor.ProcessCommand([]string{"XADD", "s", "*", "f", "v"})
+		if result != tt.expected {
+			t.Errorf("XADD * = %q, want %q", result, tt.expected)
+		}
+	}
+}
+
+func TestClockDrivesBLPOPTimeout(t *testing.T) {
+	processor, c := newManualProcessor(t)
+
+	resultChan := make(chan string, 1)
+	go func() {
+		resultChan <- processor.ProcessCommand([]string{"BLPOP", "list", "10"})
+	}()
+
+	// Wait for BLPOP to arm its timer
+	deadline := time.Now().Add(time.Second)
+	for c.Pending() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("BLPOP did not block")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Advance(9 * time.Second)
+	select {
+	case result := <-resultChan:
+		t.Fatalf("BLPOP returned %q before its timeout", result)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c.Advance(time.Second)
+	select {
+	case result := <-resultChan:
+		if result != "*-1\r\n" {
+			t.Errorf("BLPOP result = %q, want %q", result, "*-1\r\n")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP did not time out")
+	}
+}
@@ -0,0 +1,201 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Command implements the COMMAND command and its subcommands, describing the command table.
+// Example: COMMAND
+// Example: COMMAND INFO get set
+// Example: COMMAND GETKEYS SET key value
+func (p *Processor) Command(w *resp.Writer, args []string) {
+	if len(args) == 1 {
+		p.commandInfo(w, p.sortedCommandNames())
+		return
+	}
+
+	subcommand := strings.ToUpper(args[1])
+	switch subcommand {
+	case "COUNT":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("command|count"))
+			return
+		}
+		w.WriteInteger(int64(len(p.commands)))
+	case "LIST":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("command|list"))
+			return
+		}
+		w.WriteBulkArray(p.sortedCommandNames())
+	case "INFO":
+		names := args[2:]
+		if len(names) == 0 {
+			names = p.sortedCommandNames()
+		}
+		p.commandInfo(w, names)
+	case "DOCS":
+		// Command documentation is not available, clients fall back to COMMAND INFO
+		w.WriteMapHeader(0)
+	case "GETKEYS":
+		if len(args) < 3 {
+			w.WriteError(wrongArityError("command|getkeys"))
+			return
+		}
+		p.commandGetKeys(w, args[2:])
+	default:
+		w.WriteError("ERR unknown subcommand '" + resp.ErrorArg(args[1]) + "'. Try COMMAND HELP.")
+	}
+}
+
+// commandInfo writes the COMMAND INFO reply for the given command names.
+// Unknown commands are reported as null entries.
+func (p *Processor) commandInfo(w *resp.Writer, names []string) {
+	w.WriteArrayHeader(len(names))
+	for _, name := range names {
+		cmd, exists := p.LookupCommand(name)
+		if !exists {
+			w.WriteNullArray()
+			continue
+		}
+		describeCommand(w, cmd)
+	}
+}
+
+// commandGetKeys writes the keys of a full command invocation, e.g. ["SET", "key", "value"].
+func (p *Processor) commandGetKeys(w *resp.Writer, call []string) {
+	cmd, exists := p.LookupCommand(call[0])
+	if !exists {
+		w.WriteError("ERR Invalid command specified")
+		return
+	}
+	if !cmd.CheckArity(len(call)) {
+		w.WriteError("ERR Invalid number of arguments specified for command")
+		return
+	}
+
+	keys := cmd.Keys(call)
+	if len(keys) == 0 {
+		w.WriteError("ERR The command has no key arguments")
+		return
+	}
+	w.WriteBulkArray(keys)
+}
+
+// sortedCommandNames returns the names of all known commands in alphabetical order.
+func (p *Processor) sortedCommandNames() []string {
+	names := make([]string, 0, len(p.commands))
+	for name := range p.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describeCommand writes the COMMAND INFO entry of a command:
+// name, arity, flags, first key, last key, step, ACL categories, tips, key specs and subcommands.
+// Flags and categories are sets and key specs are maps for RESP3 clients.
+func describeCommand(w *resp.Writer, cmd *Command) {
+	w.WriteArrayHeader(10)
+	w.WriteBulk(cmd.Name)
+	w.WriteInteger(int64(cmd.Arity))
+
+	w.WriteSetHeader(len(cmd.Flags))
+	for _, flag := range cmd.Flags {
+		w.WriteSimpleString(flag)
+	}
+
+	w.WriteInteger(int64(cmd.FirstKey))
+	w.WriteInteger(int64(cmd.LastKey))
+	w.WriteInteger(int64(cmd.Step))
+
+	categories := aclCategories(cmd)
+	w.WriteSetHeader(len(categories))
+	for _, category := range categories {
+		w.WriteSimpleString(category)
+	}
+
+	// Tips
+	w.WriteArrayHeader(0)
+	describeKeySpecs(w, cmd)
+	// Subcommands
+	w.WriteArrayHeader(0)
+}
+
+// aclCategories derives the ACL categories of a command from its flags and group.
+func aclCategories(cmd *Command) []string {
+	var categories []string
+	if cmd.HasFlag(FlagWrite) {
+		categories = append(categories, "@write")
+	}
+	if cmd.HasFlag(FlagReadonly) {
+		categories = append(categories, "@read")
+	}
+	if cmd.Group != "" {
+		categories = append(categories, "@"+cmd.Group)
+	}
+	if cmd.HasFlag(FlagAdmin) {
+		categories = append(categories, "@admin", "@dangerous")
+	}
+	if cmd.HasFlag(FlagFast) {
+		categories = append(categories, "@fast")
+	} else {
+		categories = append(categories, "@slow")
+	}
+	if cmd.HasFlag(FlagBlocking) {
+		categories = append(categories, "@blocking")
+	}
+	return categories
+}
+
+// describeKeySpecs writes the key specifications of a command from its key positions.
+// Every command of the table has its keys in a single range starting at a fixed index.
+func describeKeySpecs(w *resp.Writer, cmd *Command) {
+	if cmd.FirstKey == 0 {
+		w.WriteArrayHeader(0)
+		return
+	}
+
+	access := "RO"
+	if cmd.HasFlag(FlagWrite) {
+		access = "RW"
+	}
+
+	// The range spec counts the last key relative to the first one, unless it counts from the end
+	lastKey := cmd.LastKey
+	if lastKey >= 0 {
+		lastKey -= cmd.FirstKey
+	}
+
+	w.WriteArrayHeader(1)
+	w.WriteMapHeader(3)
+
+	w.WriteBulk("flags")
+	w.WriteSetHeader(1)
+	w.WriteSimpleString(access)
+
+	w.WriteBulk("begin_search")
+	w.WriteMapHeader(2)
+	w.WriteBulk("type")
+	w.WriteBulk("index")
+	w.WriteBulk("spec")
+	w.WriteMapHeader(1)
+	w.WriteBulk("index")
+	w.WriteInteger(int64(cmd.FirstKey))
+
+	w.WriteBulk("find_keys")
+	w.WriteMapHeader(2)
+	w.WriteBulk("type")
+	w.WriteBulk("range")
+	w.WriteBulk("spec")
+	w.WriteMapHeader(3)
+	w.WriteBulk("lastkey")
+	w.WriteInteger(int64(lastKey))
+	w.WriteBulk("keystep")
+	w.WriteInteger(int64(cmd.Step))
+	w.WriteBulk("limit")
+	w.WriteInteger(0)
+}
@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCommandTableDispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "Typo in command name",
+			input:    []string{"GTE", "foo"},
+			expected: "-ERR unknown command 'GTE', with args beginning with: 'foo' \r\n",
+		},
+		{
+			name:     "Too few arguments",
+			input:    []string{"GET"},
+			expected: "-ERR wrong number of arguments for 'get' command\r\n",
+		},
+		{
+			name:     "Too many arguments for a fixed arity",
+			input:    []string{"GET", "a", "b"},
+			expected: "-ERR wrong number of arguments for 'get' command\r\n",
+		},
+		{
+			name:     "Too few arguments for a variable arity",
+			input:    []string{"xadd", "s", "*", "f"},
+			expected: "-ERR wrong number of arguments for 'xadd' command\r\n",
+		},
+		{
+			name:     "Mixed case command name",
+			input:    []string{"GeT", "missing"},
+			expected: "$-1\r\n",
+		},
+	}
+
+	processor := NewProcessor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandSubcommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "COMMAND GETKEYS single key",
+			input:    []string{"COMMAND", "GETKEYS", "SET", "key", "value"},
+			expected: "*1\r\n$3\r\nkey\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS all arguments are keys",
+			input:    []string{"COMMAND", "GETKEYS", "DEL", "a", "b", "c"},
+			expected: "*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS keys before a trailing argument",
+			input:    []string{"COMMAND", "GETKEYS", "BLPOP", "a", "b", "0"},
+			expected: "*2\r\n$1\r\na\r\n$1\r\nb\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS two keys",
+			input:    []string{"COMMAND", "GETKEYS", "RENAME", "a", "b"},
+			expected: "*2\r\n$1\r\na\r\n$1\r\nb\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS unknown command",
+			input:    []string{"COMMAND", "GETKEYS", "FOO", "a"},
+			expected: "-ERR Invalid command specified\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS wrong arity",
+			input:    []string{"COMMAND", "GETKEYS", "GET"},
+			expected: "-ERR Invalid number of arguments specified for command\r\n",
+		},
+		{
+			name:     "COMMAND GETKEYS command without keys",
+			input:    []string{"COMMAND", "GETKEYS", "PING"},
+			expected: "-ERR The command has no key arguments\r\n",
+		},
+		{
+			name:     "COMMAND INFO unknown command",
+			input:    []string{"COMMAND", "INFO", "foo"},
+			expected: "*1\r\n*-1\r\n",
+		},
+		{
+			name:     "COMMAND unknown subcommand",
+			input:    []string{"COMMAND", "FOO"},
+			expected: "-ERR unknown subcommand 'FOO'. Try COMMAND HELP.\r\n",
+		},
+	}
+
+	processor := NewProcessor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandCount(t *testing.T) {
+	processor := NewProcessor()
+
+	result := processor.ProcessCommand([]string{"COMMAND", "COUNT"})
+	expected := ":" + strconv.Itoa(len(processor.commands)) + "\r\n"
+	if result != expected {
+		t.Errorf("COMMAND COUNT = %q, want %q", result, expected)
+	}
+
+	// COMMAND and COMMAND INFO without names describe every command
+	result = processor.ProcessCommand([]string{"COMMAND"})
+	prefix := "*" + strconv.Itoa(len(processor.commands)) + "\r\n"
+	if !strings.HasPrefix(result, prefix) {
+		t.Errorf("COMMAND reply starts with %q, want %q", result[:10], prefix)
+	}
+}
+
+func TestCommandInfo(t *testing.T) {
+	processor := NewProcessor()
+
+	result := processor.ProcessCommand([]string{"COMMAND", "INFO", "GET"})
+	expected := "*1\r\n*10\r\n" +
+		"$3\r\nget\r\n" +
+		":2\r\n" +
+		"*2\r\n+readonly\r\n+fast\r\n" +
+		":1\r\n:1\r\n:1\r\n" +
+		"*3\r\n+@read\r\n+@string\r\n+@fast\r\n" +
+		"*0\r\n" +
+		"*1\r\n*6\r\n" +
+		"$5\r\nflags\r\n*1\r\n+RO\r\n" +
+		"$12\r\nbegin_search\r\n*4\r\n$4\r\ntype\r\n$5\r\nindex\r\n$4\r\nspec\r\n*2\r\n$5\r\nindex\r\n:1\r\n" +
+		"$9\r\nfind_keys\r\n*4\r\n$4\r\ntype\r\n$5\r\nrange\r\n$4\r\nspec\r\n" +
+		"*6\r\n$7\r\nlastkey\r\n:0\r\n$7\r\nkeystep\r\n:1\r\n$5\r\nlimit\r\n:0\r\n" +
+		"*0\r\n"
+	if result != expected {
+		t.Errorf("COMMAND INFO GET = %q, want %q", result, expected)
+	}
+}
+
+func TestCommandTableIsConsistent(t *testing.T) {
+	processor := NewProcessor()
+	for name, cmd := range processor.commands {
+		if name != strings.ToLower(name) {
+			t.Errorf("Command %q must be registered in lowercase", name)
+		}
+		if cmd.Arity == 0 {
+			t.Errorf("Command %q has no arity", name)
+		}
+		if cmd.FirstKey != 0 && cmd.Step == 0 {
+			t.Errorf("Command %q has keys but no step", name)
+		}
+		if cmd.HasFlag(FlagWrite) == cmd.HasFlag(FlagReadonly) && cmd.FirstKey != 0 {
+			t.Errorf("Command %q with keys must be either write or readonly", name)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/metrics"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Command flags, as reported by COMMAND INFO.
+const (
+	// FlagWrite marks commands that may modify the keyspace
+	FlagWrite = "write"
+	// FlagReadonly marks commands that only read from the keyspace
+	FlagReadonly = "readonly"
+	// FlagDenyOOM marks commands that may increase memory usage
+	FlagDenyOOM = "denyoom"
+	// FlagFast marks commands that run in constant or logarithmic time
+	FlagFast = "fast"
+	// FlagBlocking marks commands that may block the client
+	FlagBlocking = "blocking"
+	// FlagAdmin marks administrative commands
+	FlagAdmin = "admin"
+	// FlagLoading marks commands allowed while the server is loading data
+	FlagLoading = "loading"
+	// FlagStale marks commands allowed while a replica has stale data
+	FlagStale = "stale"
+	// FlagNoAuth marks commands allowed before the client authenticates
+	FlagNoAuth = "no-auth"
+)
+
+// maxUnknownArgsLen is the most bytes of arguments echoed in the unknown command error, as in Redis
+const maxUnknownArgsLen = 128
+
+// Command describes a command known to the server.
+type Command struct {
+	// Name is the lowercase name of the command
+	Name string
+	// Arity is the number of arguments including the command name.
+	// A negative arity -N means at least N arguments.
+	Arity int
+	// Flags describe the behaviour of the command, see the Flag constants
+	Flags []string
+	// Group is the data type or area the command belongs to, e.g. "string" or "keyspace"
+	Group string
+	// FirstKey is the position of the first key argument, 0 if the command takes no keys
+	FirstKey int
+	// LastKey is the position of the last key argument.
+	// Negative values count from the end, -1 being the last argument.
+	LastKey int
+	// Step is the distance between two key arguments
+	Step int
+	// Handler executes the command and writes its reply
+	Handler func(w *resp.Writer, args []string)
+	// ClientHandler is used instead of Handler by commands that read or change the state
+	// of the calling client
+	ClientHandler func(c *client.Client, w *resp.Writer, args []string)
+	// BlockingHandler is used instead of Handler by blocking commands. The command calls block
+	// right before waiting, which marks the client as blocked, and must stop waiting once the
+	// returned context is cancelled, e.g. by CLIENT UNBLOCK, replying according to its cause.
+	BlockingHandler func(block func() context.Context, w *resp.Writer, args []string)
+
+	// stats counts the calls to the command, reported by INFO commandstats
+	stats commandStats
+}
+
+// HasFlag reports whether the command has the given flag.
+func (c *Command) HasFlag(flag string) bool {
+	for _, f := range c.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckArity reports whether argc arguments, including the command name, are valid for the command.
+func (c *Command) CheckArity(argc int) bool {
+	if c.Arity >= 0 {
+		return argc == c.Arity
+	}
+	return argc >= -c.Arity
+}
+
+// Keys returns the key arguments of a call to the command.
+func (c *Command) Keys(args []string) []string {
+	if c.FirstKey == 0 || c.FirstKey >= len(args) {
+		return nil
+	}
+
+	last := c.LastKey
+	if last < 0 {
+		last = len(args) + last
+	}
+	if last >= len(args) {
+		last = len(args) - 1
+	}
+
+	var keys []string
+	for i := c.FirstKey; i <= last; i += c.Step {
+		keys = append(keys, args[i])
+	}
+	return keys
+}
+
+// registerCommands builds the command table.
+func (p *Processor) registerCommands() {
+	commands := []*Command{
+		// Connection
+		{Name: "ping", Arity: -1, Flags: []string{FlagFast, FlagStale}, Group: "connection", Handler: p.ping},
+		{Name: "echo", Arity: 2, Flags: []string{FlagFast, FlagStale}, Group: "connection", Handler: p.StringStore.Echo},
+		{Name: "hello", Arity: -1, Flags: []string{FlagFast, FlagLoading, FlagStale, FlagNoAuth}, Group: "connection", ClientHandler: p.Hello},
+		{Name: "auth", Arity: -2, Flags: []string{FlagFast, FlagLoading, FlagStale, FlagNoAuth}, Group: "connection", ClientHandler: p.Auth},
+
+		// Strings
+		{Name: "set", Arity: -3, Flags: []string{FlagWrite, FlagDenyOOM}, Group: "string", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.StringStore.Set},
+		{Name: "get", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "string", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.StringStore.Get},
+
+		// Lists
+		{Name: "rpush", Arity: -3, Flags: []string{FlagWrite, FlagDenyOOM, FlagFast}, Group: "list", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.ListStore.RPush},
+		{Name: "lpush", Arity: -3, Flags: []string{FlagWrite, FlagDenyOOM, FlagFast}, Group: "list", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.ListStore.LPush},
+		{Name: "lrange", Arity: 4, Flags: []string{FlagReadonly}, Group: "list", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.ListStore.LRange},
+		{Name: "llen", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "list", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.ListStore.LLen},
+		{Name: "lpop", Arity: -2, Flags: []string{FlagWrite, FlagFast}, Group: "list", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.ListStore.LPop},
+		{Name: "blpop", Arity: -3, Flags: []string{FlagWrite, FlagBlocking}, Group: "list", FirstKey: 1, LastKey: -2, Step: 1, BlockingHandler: p.ListStore.BLPop},
+
+		// Streams
+		{Name: "xadd", Arity: -5, Flags: []string{FlagWrite, FlagDenyOOM, FlagFast}, Group: "stream", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.StreamStore.XAdd},
+		{Name: "xrange", Arity: -4, Flags: []string{FlagReadonly}, Group: "stream", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.StreamStore.XRange},
+
+		// Generic keyspace
+		{Name: "type", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.TypeStore.Type},
+		{Name: "del", Arity: -2, Flags: []string{FlagWrite}, Group: "keyspace", FirstKey: 1, LastKey: -1, Step: 1, Handler: p.KeyStore.Del},
+		{Name: "unlink", Arity: -2, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: -1, Step: 1, Handler: p.KeyStore.Unlink},
+		{Name: "exists", Arity: -2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: -1, Step: 1, Handler: p.KeyStore.Exists},
+		{Name: "touch", Arity: -2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: -1, Step: 1, Handler: p.KeyStore.Touch},
+		{Name: "rename", Arity: 3, Flags: []string{FlagWrite}, Group: "keyspace", FirstKey: 1, LastKey: 2, Step: 1, Handler: p.KeyStore.Rename},
+		{Name: "renamenx", Arity: 3, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 2, Step: 1, Handler: p.KeyStore.RenameNX},
+		{Name: "copy", Arity: -3, Flags: []string{FlagWrite, FlagDenyOOM}, Group: "keyspace", FirstKey: 1, LastKey: 2, Step: 1, Handler: p.KeyStore.Copy},
+		{Name: "expire", Arity: -3, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.Expire},
+		{Name: "pexpire", Arity: -3, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.PExpire},
+		{Name: "expireat", Arity: -3, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.ExpireAt},
+		{Name: "pexpireat", Arity: -3, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.PExpireAt},
+		{Name: "ttl", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.TTL},
+		{Name: "pttl", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.PTTL},
+		{Name: "expiretime", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.ExpireTime},
+		{Name: "pexpiretime", Arity: 2, Flags: []string{FlagReadonly, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.PExpireTime},
+		{Name: "persist", Arity: 2, Flags: []string{FlagWrite, FlagFast}, Group: "keyspace", FirstKey: 1, LastKey: 1, Step: 1, Handler: p.KeyStore.Persist},
+
+		// Server
+		{Name: "client", Arity: -2, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "connection", ClientHandler: p.ClientCommand},
+		{Name: "command", Arity: -1, Flags: []string{FlagLoading, FlagStale}, Group: "server", Handler: p.Command},
+		{Name: "config", Arity: -2, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "server", Handler: p.configCommand},
+		{Name: "info", Arity: -1, Flags: []string{FlagLoading, FlagStale}, Group: "server", Handler: p.Info},
+		{Name: "monitor", Arity: 1, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "server", ClientHandler: p.MonitorCommand},
+		{Name: "slowlog", Arity: -2, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "server", Handler: p.Slowlog},
+		{Name: "debug", Arity: -2, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "server", ClientHandler: p.Debug},
+		{Name: "shutdown", Arity: -1, Flags: []string{FlagAdmin, FlagLoading, FlagStale}, Group: "server", Handler: p.Shutdown},
+	}
+
+	p.commands = make(map[string]*Command, len(commands))
+	for _, cmd := range commands {
+		cmd.stats.latency = metrics.NewHistogram(metrics.LatencyBuckets)
+		p.commands[cmd.Name] = cmd
+	}
+}
+
+// LookupCommand returns the command with the given name, case insensitively.
+func (p *Processor) LookupCommand(name string) (*Command, bool) {
+	cmd, exists := p.commands[strings.ToLower(name)]
+	return cmd, exists
+}
+
+// ping replies to PING, echoing the message when one is given.
+// Example: PING
+// Example: PING hello
+func (p *Processor) ping(w *resp.Writer, args []string) {
+	switch len(args) {
+	case 1:
+		w.WriteSimpleString("PONG")
+	case 2:
+		w.WriteBulk(args[1])
+	default:
+		w.WriteError(wrongArityError("ping"))
+	}
+}
+
+// unknownCommandError builds the error returned for commands missing from the table.
+// As in Redis, the arguments are echoed until they add up to 128 bytes.
+func unknownCommandError(args []string) string {
+	var quoted strings.Builder
+	for _, arg := range args[1:] {
+		if quoted.Len() >= maxUnknownArgsLen {
+			break
+		}
+		arg = arg[:min(len(arg), maxUnknownArgsLen-quoted.Len())]
+		quoted.WriteString(fmt.Sprintf("'%s' ", arg))
+	}
+	return fmt.Sprintf("ERR unknown command '%s', with args beginning with: %s", resp.ErrorArg(args[0]), quoted.String())
+}
+
+// wrongArityError builds the error returned when a command is called with a wrong number of arguments.
+func wrongArityError(name string) string {
+	return fmt.Sprintf("ERR wrong number of arguments for '%s' command", name)
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// configCommand implements the CONFIG command, reading and changing the configuration at runtime.
+// Example: CONFIG GET max*
+// Example: CONFIG SET timeout 300 maxmemory 100mb
+// Example: CONFIG REWRITE
+// Example: CONFIG RESETSTAT
+func (p *Processor) configCommand(w *resp.Writer, args []string) {
+	subcommand := strings.ToUpper(args[1])
+	switch subcommand {
+	case "GET":
+		if len(args) < 3 {
+			w.WriteError(wrongArityError("config|get"))
+			return
+		}
+		pairs := p.Config.Get(args[2:])
+		w.WriteMapHeader(len(pairs) / 2)
+		for _, item := range pairs {
+			w.WriteBulk(item)
+		}
+	case "SET":
+		if len(args) < 4 || len(args)%2 != 0 {
+			w.WriteError(wrongArityError("config|set"))
+			return
+		}
+		if err := p.Config.Set(args[2:]); err != nil {
+			w.WriteError("ERR " + err.Error())
+			return
+		}
+		w.WriteSimpleString("OK")
+	case "REWRITE":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("config|rewrite"))
+			return
+		}
+		if err := p.Config.Rewrite(); err != nil {
+			if errors.Is(err, config.ErrNoConfigFile) {
+				w.WriteError("ERR " + err.Error())
+				return
+			}
+			w.WriteError("ERR Rewriting config file: " + err.Error())
+			return
+		}
+		w.WriteSimpleString("OK")
+	case "RESETSTAT":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("config|resetstat"))
+			return
+		}
+		p.ResetStats()
+		w.WriteSimpleString("OK")
+	default:
+		w.WriteError("ERR unknown subcommand '" + resp.ErrorArg(args[1]) + "'. Try CONFIG HELP.")
+	}
+}
+
+// ResetStats sets the server statistics reported by INFO back to zero.
+func (p *Processor) ResetStats() {
+	p.Keyspace.ResetStats()
+	p.stats.reset()
+	for _, cmd := range p.commands {
+		cmd.stats.reset()
+	}
+}
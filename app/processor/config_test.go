@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+)
+
+func TestConfigCommand(t *testing.T) {
+	processor := NewProcessor()
+
+	steps := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "CONFIG GET a single parameter",
+			input:    []string{"CONFIG", "GET", "appendonly"},
+			expected: "*2\r\n$10\r\nappendonly\r\n$2\r\nno\r\n",
+		},
+		{
+			name:     "CONFIG GET with a glob pattern",
+			input:    []string{"CONFIG", "GET", "maxmemory*"},
+			expected: "*4\r\n$9\r\nmaxmemory\r\n$1\r\n0\r\n$16\r\nmaxmemory-policy\r\n$10\r\nnoeviction\r\n",
+		},
+		{
+			name:     "CONFIG GET an unknown parameter",
+			input:    []string{"CONFIG", "GET", "nothing"},
+			expected: "*0\r\n",
+		},
+		{
+			name:     "CONFIG SET several parameters",
+			input:    []string{"config", "set", "timeout", "300", "maxmemory", "100mb"},
+			expected: "+OK\r\n",
+		},
+		{
+			name:     "CONFIG GET after CONFIG SET",
+			input:    []string{"CONFIG", "GET", "timeout", "maxmemory"},
+			expected: "*4\r\n$7\r\ntimeout\r\n$3\r\n300\r\n$9\r\nmaxmemory\r\n$9\r\n104857600\r\n",
+		},
+		{
+			name:     "CONFIG SET an immutable parameter",
+			input:    []string{"CONFIG", "SET", "port", "7000"},
+			expected: "-ERR CONFIG SET failed (possibly related to argument 'port') - can't set immutable config\r\n",
+		},
+		{
+			name:     "CONFIG SET an unknown parameter",
+			input:    []string{"CONFIG", "SET", "foo", "bar"},
+			expected: "-ERR Unknown option or number of arguments for CONFIG SET - 'foo'\r\n",
+		},
+		{
+			name:     "CONFIG SET without a value",
+			input:    []string{"CONFIG", "SET", "timeout", "1", "maxmemory"},
+			expected: "-ERR wrong number of arguments for 'config|set' command\r\n",
+		},
+		{
+			name:     "CONFIG GET without a pattern",
+			input:    []string{"CONFIG", "GET"},
+			expected: "-ERR wrong number of arguments for 'config|get' command\r\n",
+		},
+		{
+			name:     "CONFIG REWRITE without a config file",
+			input:    []string{"CONFIG", "REWRITE"},
+			expected: "-ERR The server is running without a config file\r\n",
+		},
+		{
+			name:     "CONFIG unknown subcommand",
+			input:    []string{"CONFIG", "FOO"},
+			expected: "-ERR unknown subcommand 'FOO'. Try CONFIG HELP.\r\n",
+		},
+	}
+
+	for _, step := range steps {
+		result := processor.ProcessCommand(step.input)
+		if result != step.expected {
+			t.Fatalf("%s: ProcessCommand(%v) = %q, want %q", step.name, step.input, result, step.expected)
+		}
+	}
+}
+
+func TestConfigGetRESP3(t *testing.T) {
+	processor := NewProcessor()
+	c := processor.NewClient()
+	c.Protocol = 3
+
+	result := execute(processor, c, []string{"CONFIG", "GET", "port"})
+	expected := "%1\r\n$4\r\nport\r\n$4\r\n6379\r\n"
+	if result != expected {
+		t.Errorf("CONFIG GET port = %q, want %q", result, expected)
+	}
+}
+
+func TestConfigSetRequirePassIsHotApplied(t *testing.T) {
+	processor := NewProcessor()
+
+	if result := processor.ProcessCommand([]string{"CONFIG", "SET", "requirepass", "secret"}); result != "+OK\r\n" {
+		t.Fatalf("CONFIG SET requirepass = %q", result)
+	}
+
+	// New connections must now authenticate
+	if result := processor.ProcessCommand([]string{"PING"}); result != "-NOAUTH Authentication required.\r\n" {
+		t.Errorf("PING = %q", result)
+	}
+}
+
+func TestConfigSetMaxMemoryIsHotApplied(t *testing.T) {
+	processor := NewProcessor()
+	processor.ProcessCommand([]string{"SET", "key", "value"})
+
+	if result := processor.ProcessCommand([]string{"CONFIG", "SET", "maxmemory", "1"}); result != "+OK\r\n" {
+		t.Fatalf("CONFIG SET maxmemory = %q", result)
+	}
+
+	// The limit is exceeded: writes growing the dataset fail, reads and deletes still run
+	if result := processor.ProcessCommand([]string{"SET", "other", "value"}); result != "-OOM command not allowed when used memory > 'maxmemory'.\r\n" {
+		t.Errorf("SET = %q", result)
+	}
+	if result := processor.ProcessCommand([]string{"GET", "key"}); result != "$5\r\nvalue\r\n" {
+		t.Errorf("GET = %q", result)
+	}
+	if result := processor.ProcessCommand([]string{"DEL", "key"}); result != ":1\r\n" {
+		t.Errorf("DEL = %q", result)
+	}
+
+	processor.ProcessCommand([]string{"CONFIG", "SET", "maxmemory", "0"})
+	if result := processor.ProcessCommand([]string{"SET", "other", "value"}); result != "+OK\r\n" {
+		t.Errorf("SET without a limit = %q", result)
+	}
+}
+
+func TestConfigResetStat(t *testing.T) {
+	manual := clock.NewManual(time.UnixMilli(1_000_000))
+	processor := NewProcessorWithOptions(Options{Clock: manual})
+
+	processor.ProcessCommand([]string{"SET", "key", "value", "PX", "10"})
+	manual.Advance(20 * time.Millisecond)
+	processor.ProcessCommand([]string{"GET", "key"})
+	if processor.Keyspace.ExpireStats().ExpiredKeys != 1 {
+		t.Fatal("Expected the expired key to be counted")
+	}
+
+	if result := processor.ProcessCommand([]string{"CONFIG", "RESETSTAT"}); result != "+OK\r\n" {
+		t.Fatalf("CONFIG RESETSTAT = %q", result)
+	}
+	if processor.Keyspace.ExpireStats().ExpiredKeys != 0 {
+		t.Error("Expected CONFIG RESETSTAT to reset the expired keys counter")
+	}
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// errDebugNotAllowed is replied to DEBUG unless the enable-debug-command setting allows it
+const errDebugNotAllowed = "ERR DEBUG command not allowed. If the enable-debug-command option is set to \"local\", " +
+	"you can run it from a local connection, otherwise you need to set this option in the configuration file, " +
+	"and then restart the server."
+
+// Debug implements a subset of the DEBUG command used to drive the server in tests.
+// It can move the server clock, so it is refused unless enable-debug-command allows it.
+// Example: DEBUG SET-ACTIVE-EXPIRE 0
+// Example: DEBUG JUMP-TIME 60000
+func (p *Processor) Debug(c *client.Client, w *resp.Writer, args []string) {
+	if !debugAllowed(p.Config.Current().EnableDebugCommand, c) {
+		w.WriteError(errDebugNotAllowed)
+		return
+	}
+	if len(args) < 2 {
+		w.WriteError("ERR wrong number of arguments for 'debug' command")
+		return
+	}
+
+	subcommand := strings.ToUpper(args[1])
+	switch subcommand {
+	case "SET-ACTIVE-EXPIRE":
+		// DEBUG SET-ACTIVE-EXPIRE <0|1> toggles the background active expire cycle
+		if len(args) != 3 || (args[2] != "0" && args[2] != "1") {
+			w.WriteError("ERR syntax error")
+			return
+		}
+		p.Keyspace.SetActiveExpire(args[2] == "1")
+		w.WriteSimpleString("OK")
+	case "JUMP-TIME":
+		// DEBUG JUMP-TIME <milliseconds> moves the server clock, negative values move it backwards
+		if len(args) != 3 {
+			w.WriteError("ERR syntax error")
+			return
+		}
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			w.WriteError("ERR value is not an integer or out of range")
+			return
+		}
+		jumper, ok := p.Keyspace.Clock().(clock.Jumper)
+		if !ok {
+			w.WriteError("ERR the server clock cannot be moved")
+			return
+		}
+		jumper.Jump(time.Duration(ms) * time.Millisecond)
+		w.WriteSimpleString("OK")
+	default:
+		w.WriteError("ERR unknown subcommand '" + resp.ErrorArg(args[1]) + "'. Try DEBUG HELP.")
+	}
+}
+
+// debugAllowed reports whether the enable-debug-command setting lets c run DEBUG.
+// With local, only the unix socket, loopback and internal clients are allowed.
+func debugAllowed(setting string, c *client.Client) bool {
+	switch setting {
+	case "yes":
+		return true
+	case "local":
+		if c.UnixSocket || c.Addr == "" {
+			return true
+		}
+		host, _, err := net.SplitHostPort(c.Addr)
+		if err != nil {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	default:
+		return false
+	}
+}
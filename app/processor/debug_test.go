@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// newDebugProcessor creates a processor allowing DEBUG, telling the time with c unless nil.
+func newDebugProcessor(c clock.Clock) *Processor {
+	cfg := config.Default()
+	cfg.EnableDebugCommand = "yes"
+	return NewProcessorWithOptions(Options{Config: cfg, Clock: c})
+}
+
+func TestDebug(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "DEBUG without subcommand",
+			input:    []string{"DEBUG"},
+			expected: "-ERR wrong number of arguments for 'debug' command\r\n",
+		},
+		{
+			name:     "DEBUG SET-ACTIVE-EXPIRE 0",
+			input:    []string{"DEBUG", "SET-ACTIVE-EXPIRE", "0"},
+			expected: "+OK\r\n",
+		},
+		{
+			name:     "DEBUG SET-ACTIVE-EXPIRE with invalid value",
+			input:    []string{"DEBUG", "set-active-expire", "yes"},
+			expected: "-ERR syntax error\r\n",
+		},
+		{
+			name:     "DEBUG JUMP-TIME",
+			input:    []string{"DEBUG", "JUMP-TIME", "1000"},
+			expected: "+OK\r\n",
+		},
+		{
+			name:     "DEBUG JUMP-TIME with invalid value",
+			input:    []string{"DEBUG", "JUMP-TIME", "soon"},
+			expected: "-ERR value is not an integer or out of range\r\n",
+		},
+		{
+			name:     "DEBUG unknown subcommand",
+			input:    []string{"DEBUG", "FOO"},
+			expected: "-ERR unknown subcommand 'FOO'. Try DEBUG HELP.\r\n",
+		},
+	}
+
+	processor := newDebugProcessor(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDebugJumpTimeExpiresKeys(t *testing.T) {
+	processor := newDebugProcessor(nil)
+	processor.ProcessCommand([]string{"SET", "k", "v", "EX", "60"})
+
+	processor.ProcessCommand([]string{"DEBUG", "JUMP-TIME", "30000"})
+	if result := processor.ProcessCommand([]string{"TTL", "k"}); result != ":30\r\n" {
+		t.Errorf("TTL after jumping 30s = %q, want %q", result, ":30\r\n")
+	}
+
+	processor.ProcessCommand([]string{"DEBUG", "JUMP-TIME", "31000"})
+	if result := processor.ProcessCommand([]string{"GET", "k"}); result != "$-1\r\n" {
+		t.Errorf("GET after jumping past the expiry = %q, want null", result)
+	}
+}
+
+func TestDebugSetActiveExpire(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1700000000000))
+	processor := newDebugProcessor(c)
+	processor.ProcessCommand([]string{"SET", "k", "v", "PX", "10"})
+	c.Advance(time.Second)
+
+	processor.ProcessCommand([]string{"DEBUG", "SET-ACTIVE-EXPIRE", "0"})
+	processor.Keyspace.ActiveExpireCycle()
+	if stats := processor.Keyspace.ExpireStats(); stats.ExpiredKeys != 0 {
+		t.Errorf("Expected no key to be expired while the cycle is disabled, got %d", stats.ExpiredKeys)
+	}
+
+	processor.ProcessCommand([]string{"DEBUG", "SET-ACTIVE-EXPIRE", "1"})
+	processor.Keyspace.ActiveExpireCycle()
+	if stats := processor.Keyspace.ExpireStats(); stats.ExpiredKeys != 1 {
+		t.Errorf("Expected the key to be expired once the cycle is enabled, got %d", stats.ExpiredKeys)
+	}
+}
+
+func TestDebugNotAllowed(t *testing.T) {
+	const notAllowed = "-ERR DEBUG command not allowed. If the enable-debug-command option is set to \"local\", " +
+		"you can run it from a local connection, otherwise you need to set this option in the configuration file, " +
+		"and then restart the server.\r\n"
+
+	tests := []struct {
+		name     string
+		setting  string
+		addr     string
+		unix     bool
+		expected string
+	}{
+		{name: "Disabled by default", setting: "no", addr: "127.0.0.1:5000", expected: notAllowed},
+		{name: "Local from a remote address", setting: "local", addr: "10.0.0.1:5000", expected: notAllowed},
+		{name: "Local from a loopback address", setting: "local", addr: "[::1]:5000", expected: "+OK\r\n"},
+		{name: "Local from the unix socket", setting: "local", addr: "/tmp/redis.sock:0", unix: true, expected: "+OK\r\n"},
+		{name: "Local from an internal client", setting: "local", addr: "", expected: "+OK\r\n"},
+		{name: "Enabled from a remote address", setting: "yes", addr: "10.0.0.1:5000", expected: "+OK\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.EnableDebugCommand = tt.setting
+			processor := NewProcessorWithOptions(Options{Config: cfg})
+			c := processor.NewClient()
+			c.Addr = tt.addr
+			c.UnixSocket = tt.unix
+
+			result := resp.Record(func(w *resp.Writer) {
+				processor.Execute(c, w, []string{"DEBUG", "JUMP-TIME", "1000"})
+			})
+			if result != tt.expected {
+				t.Errorf("DEBUG JUMP-TIME = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
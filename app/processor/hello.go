@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// Hello implements the HELLO command, negotiating the protocol of the connection and
+// optionally authenticating and naming it in the same round trip.
+// The client keeps its previous state when any part of the command fails.
+// Example: HELLO
+// Example: HELLO 3 AUTH default secret SETNAME worker-1
+func (p *Processor) Hello(c *client.Client, w *resp.Writer, args []string) {
+	protocol := c.Protocol
+	next := 1
+	if len(args) > 1 {
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			w.WriteError("ERR Protocol version is not an integer or out of range")
+			return
+		}
+		if version < client.RESP2 || version > client.RESP3 {
+			w.WriteError("NOPROTO sorry, this protocol version is not supported.")
+			return
+		}
+		protocol = int(version)
+		next = 2
+	}
+
+	var username, password, name string
+	var auth, setName bool
+	for i := next; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		option := strings.ToUpper(args[i])
+		switch {
+		case option == "AUTH" && remaining >= 2:
+			username, password = args[i+1], args[i+2]
+			auth = true
+			i += 2
+		case option == "SETNAME" && remaining >= 1:
+			name = args[i+1]
+			setName = true
+			i++
+		default:
+			w.WriteError("ERR Syntax error in HELLO option '" + resp.ErrorArg(args[i]) + "'")
+			return
+		}
+	}
+
+	if auth {
+		if err := p.authenticate(username, password); err != "" {
+			w.WriteError(err)
+			return
+		}
+	} else if !c.Authenticated {
+		w.WriteError("NOAUTH HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+		return
+	}
+	if setName {
+		if !client.ValidName(name) {
+			w.WriteError("ERR Client names cannot contain spaces, newlines or special characters.")
+			return
+		}
+	}
+
+	c.Lock()
+	if setName {
+		c.Name = name
+	}
+	if auth {
+		c.Authenticated = true
+	}
+	c.Protocol = protocol
+	c.Unlock()
+	w.SetProtocol(protocol)
+
+	w.WriteMapHeader(7)
+	w.WriteBulk("server")
+	w.WriteBulk("redis")
+	w.WriteBulk("version")
+	w.WriteBulk(Version)
+	w.WriteBulk("proto")
+	w.WriteInteger(int64(c.Protocol))
+	w.WriteBulk("id")
+	w.WriteInteger(c.ID)
+	w.WriteBulk("mode")
+	w.WriteBulk("standalone")
+	w.WriteBulk("role")
+	w.WriteBulk("master")
+	w.WriteBulk("modules")
+	w.WriteArrayHeader(0)
+}
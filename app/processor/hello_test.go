@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+func TestHello(t *testing.T) {
+	processor := NewProcessor()
+	c := processor.NewClient()
+	id := strconv.FormatInt(c.ID, 10)
+
+	fields := func(proto string) string {
+		return "$6\r\nserver\r\n$5\r\nredis\r\n" +
+			"$7\r\nversion\r\n$5\r\n" + Version + "\r\n" +
+			"$5\r\nproto\r\n:" + proto + "\r\n" +
+			"$2\r\nid\r\n:" + id + "\r\n" +
+			"$4\r\nmode\r\n$10\r\nstandalone\r\n" +
+			"$4\r\nrole\r\n$6\r\nmaster\r\n" +
+			"$7\r\nmodules\r\n*0\r\n"
+	}
+
+	steps := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "HELLO without arguments keeps RESP2",
+			input:    []string{"HELLO"},
+			expected: "*14\r\n" + fields("2"),
+		},
+		{
+			name:     "GET of a missing key in RESP2",
+			input:    []string{"GET", "missing"},
+			expected: "$-1\r\n",
+		},
+		{
+			name:     "Unsupported protocol version",
+			input:    []string{"HELLO", "4"},
+			expected: "-NOPROTO sorry, this protocol version is not supported.\r\n",
+		},
+		{
+			name:     "Protocol version is not a number",
+			input:    []string{"HELLO", "three"},
+			expected: "-ERR Protocol version is not an integer or out of range\r\n",
+		},
+		{
+			name:     "Unknown option",
+			input:    []string{"HELLO", "3", "FOO"},
+			expected: "-ERR Syntax error in HELLO option 'FOO'\r\n",
+		},
+		{
+			name:     "AUTH without password",
+			input:    []string{"HELLO", "3", "AUTH", "default"},
+			expected: "-ERR Syntax error in HELLO option 'AUTH'\r\n",
+		},
+		{
+			name:     "AUTH with unknown user",
+			input:    []string{"HELLO", "3", "AUTH", "alice", "secret"},
+			expected: "-WRONGPASS invalid username-password pair or user is disabled.\r\n",
+		},
+		{
+			name:     "Invalid client name",
+			input:    []string{"HELLO", "3", "SETNAME", "bad name"},
+			expected: "-ERR Client names cannot contain spaces, newlines or special characters.\r\n",
+		},
+		{
+			name:     "Failed HELLO keeps RESP2",
+			input:    []string{"GET", "missing"},
+			expected: "$-1\r\n",
+		},
+		{
+			name:     "Switch to RESP3",
+			input:    []string{"HELLO", "3", "AUTH", "default", "secret", "SETNAME", "worker-1"},
+			expected: "%7\r\n" + fields("3"),
+		},
+		{
+			name:     "GET of a missing key in RESP3",
+			input:    []string{"GET", "missing"},
+			expected: "_\r\n",
+		},
+		{
+			name:     "COMMAND INFO of an unknown command in RESP3",
+			input:    []string{"COMMAND", "INFO", "foo"},
+			expected: "*1\r\n_\r\n",
+		},
+		{
+			name:     "Switch back to RESP2",
+			input:    []string{"hello", "2"},
+			expected: "*14\r\n" + fields("2"),
+		},
+	}
+
+	for _, step := range steps {
+		result := execute(processor, c, step.input)
+		if result != step.expected {
+			t.Fatalf("%s: Execute(%v) = %q, want %q", step.name, step.input, result, step.expected)
+		}
+	}
+
+	if c.Name != "worker-1" {
+		t.Errorf("Expected client name worker-1, got %q", c.Name)
+	}
+}
+
+func TestCommandInfoRESP3(t *testing.T) {
+	processor := NewProcessor()
+	c := processor.NewClient()
+	c.Protocol = client.RESP3
+
+	result := execute(processor, c, []string{"COMMAND", "INFO", "GET"})
+	expected := "*1\r\n*10\r\n" +
+		"$3\r\nget\r\n" +
+		":2\r\n" +
+		"~2\r\n+readonly\r\n+fast\r\n" +
+		":1\r\n:1\r\n:1\r\n" +
+		"~3\r\n+@read\r\n+@string\r\n+@fast\r\n" +
+		"*0\r\n" +
+		"*1\r\n%3\r\n" +
+		"$5\r\nflags\r\n~1\r\n+RO\r\n" +
+		"$12\r\nbegin_search\r\n%2\r\n$4\r\ntype\r\n$5\r\nindex\r\n$4\r\nspec\r\n%1\r\n$5\r\nindex\r\n:1\r\n" +
+		"$9\r\nfind_keys\r\n%2\r\n$4\r\ntype\r\n$5\r\nrange\r\n$4\r\nspec\r\n" +
+		"%3\r\n$7\r\nlastkey\r\n:0\r\n$7\r\nkeystep\r\n:1\r\n$5\r\nlimit\r\n:0\r\n" +
+		"*0\r\n"
+	if result != expected {
+		t.Errorf("COMMAND INFO GET = %q, want %q", result, expected)
+	}
+}
+
+// execute runs a command on behalf of c and returns the reply it wrote.
+func execute(p *Processor, c *client.Client, row []string) string {
+	return resp.Record(func(w *resp.Writer) { p.Execute(c, w, row) })
+}
@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// infoSection is a section of the INFO reply.
+type infoSection struct {
+	// name is the lowercase name used to select the section
+	name string
+	// title is the header of the section, e.g. "Server"
+	title string
+	// inDefault reports whether INFO without arguments includes the section
+	inDefault bool
+	// write appends the fields of the section to sb
+	write func(p *Processor, sb *strings.Builder)
+}
+
+// infoSections lists the sections in the order they are reported.
+var infoSections = []infoSection{
+	{name: "server", title: "Server", inDefault: true, write: (*Processor).infoServer},
+	{name: "clients", title: "Clients", inDefault: true, write: (*Processor).infoClients},
+	{name: "memory", title: "Memory", inDefault: true, write: (*Processor).infoMemory},
+	{name: "stats", title: "Stats", inDefault: true, write: (*Processor).infoStats},
+	{name: "commandstats", title: "Commandstats", write: (*Processor).infoCommandStats},
+	{name: "keyspace", title: "Keyspace", inDefault: true, write: (*Processor).infoKeyspace},
+}
+
+// Info implements the INFO command, returning server statistics as "field:value" lines
+// grouped in "# Section" blocks.
+// Sections are selected by name, "default" and no argument select the common ones while
+// "all" and "everything" select every section. Unknown sections are ignored.
+// Example: INFO
+// Example: INFO clients keyspace
+func (p *Processor) Info(w *resp.Writer, args []string) {
+	selected := make(map[string]bool)
+	if len(args) == 1 {
+		selected["default"] = true
+	}
+	for _, arg := range args[1:] {
+		selected[strings.ToLower(arg)] = true
+	}
+	all := selected["all"] || selected["everything"]
+
+	var sb strings.Builder
+	for _, section := range infoSections {
+		if !all && !selected[section.name] && !(selected["default"] && section.inDefault) {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString("# " + section.title + "\r\n")
+		section.write(p, &sb)
+	}
+
+	w.WriteVerbatim("txt", sb.String())
+}
+
+// infoField appends a "name:value" line to sb.
+func infoField(sb *strings.Builder, name string, value any) {
+	fmt.Fprintf(sb, "%s:%v\r\n", name, value)
+}
+
+// infoServer writes the Server section.
+func (p *Processor) infoServer(sb *strings.Builder) {
+	cfg := p.Config.Current()
+	now := p.clock.Now()
+	uptime := int64(now.Sub(p.stats.startTime) / time.Second)
+	executable, _ := os.Executable()
+
+	infoField(sb, "redis_version", Version)
+	infoField(sb, "redis_mode", "standalone")
+	infoField(sb, "os", runtime.GOOS+" "+runtime.GOARCH)
+	infoField(sb, "arch_bits", strconv.IntSize)
+	infoField(sb, "go_version", runtime.Version())
+	infoField(sb, "process_id", os.Getpid())
+	infoField(sb, "run_id", p.stats.runID)
+	infoField(sb, "tcp_port", cfg.Port)
+	infoField(sb, "server_time_usec", now.UnixMicro())
+	infoField(sb, "uptime_in_seconds", uptime)
+	infoField(sb, "uptime_in_days", uptime/(24*60*60))
+	infoField(sb, "executable", executable)
+	infoField(sb, "config_file", cfg.File)
+}
+
+// infoClients writes the Clients section.
+func (p *Processor) infoClients(sb *strings.Builder) {
+	infoField(sb, "connected_clients", p.stats.connectedClients.Load())
+	infoField(sb, "maxclients", p.Config.Current().MaxClients)
+	infoField(sb, "blocked_clients", p.ListStore.BlockedClients())
+	infoField(sb, "total_blocking_keys", p.ListStore.BlockingKeys())
+}
+
+// infoMemory writes the Memory section.
+func (p *Processor) infoMemory(sb *strings.Builder) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	peak := p.stats.observeMemory(mem.HeapAlloc)
+	cfg := p.Config.Current()
+
+	infoField(sb, "used_memory", mem.HeapAlloc)
+	infoField(sb, "used_memory_human", bytesToHuman(mem.HeapAlloc))
+	infoField(sb, "used_memory_rss", mem.Sys)
+	infoField(sb, "used_memory_rss_human", bytesToHuman(mem.Sys))
+	infoField(sb, "used_memory_peak", peak)
+	infoField(sb, "used_memory_peak_human", bytesToHuman(peak))
+	infoField(sb, "maxmemory", cfg.MaxMemory)
+	infoField(sb, "maxmemory_human", bytesToHuman(uint64(cfg.MaxMemory)))
+	infoField(sb, "maxmemory_policy", cfg.MaxMemoryPolicy)
+	infoField(sb, "mem_allocator", "go")
+}
+
+// infoStats writes the Stats section.
+func (p *Processor) infoStats(sb *strings.Builder) {
+	expires := p.Keyspace.ExpireStats()
+	lookups := p.Keyspace.LookupStats()
+
+	infoField(sb, "total_connections_received", p.stats.connectionsReceived.Load())
+	infoField(sb, "total_commands_processed", p.stats.commandsProcessed.Load())
+	infoField(sb, "rejected_connections", p.stats.rejectedConnections.Load())
+	infoField(sb, "expired_keys", expires.ExpiredKeys)
+	infoField(sb, "expired_stale_perc", strconv.FormatFloat(expires.ExpiredStalePerc, 'f', 2, 64))
+	infoField(sb, "keyspace_hits", lookups.Hits)
+	infoField(sb, "keyspace_misses", lookups.Misses)
+}
+
+// infoCommandStats writes the Commandstats section, one line per command called at least once.
+func (p *Processor) infoCommandStats(sb *strings.Builder) {
+	names := make([]string, 0, len(p.commands))
+	for name := range p.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		stats := &p.commands[name].stats
+		calls := stats.calls.Load()
+		rejected := stats.rejected.Load()
+		if calls == 0 && rejected == 0 {
+			continue
+		}
+		usec := stats.usec.Load()
+		perCall := 0.0
+		if calls > 0 {
+			perCall = float64(usec) / float64(calls)
+		}
+		fmt.Fprintf(sb, "cmdstat_%s:calls=%d,usec=%d,usec_per_call=%.2f,rejected_calls=%d,failed_calls=%d\r\n",
+			name, calls, usec, perCall, rejected, stats.failed.Load())
+	}
+}
+
+// infoKeyspace writes the Keyspace section, omitting the database when it is empty.
+func (p *Processor) infoKeyspace(sb *strings.Builder) {
+	p.Keyspace.Lock()
+	keys := p.Keyspace.Len()
+	expires := p.Keyspace.VolatileLen()
+	p.Keyspace.Unlock()
+	if keys == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "db0:keys=%d,expires=%d,avg_ttl=%d\r\n", keys, expires, p.Keyspace.ExpireStats().AvgTTL)
+}
+
+// bytesToHuman formats a number of bytes the way Redis does, e.g. 1.50M.
+func bytesToHuman(n uint64) string {
+	units := []string{"B", "K", "M", "G", "T", "P"}
+	value := float64(n)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatUint(n, 10) + "B"
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64) + units[unit]
+}
@@ -0,0 +1,163 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// infoFields runs INFO with args and returns the reply split into sections and fields.
+func infoFields(t *testing.T, p *Processor, args ...string) (sections []string, fields map[string]string) {
+	t.Helper()
+	reply := p.ProcessCommand(append([]string{"INFO"}, args...))
+	if !strings.HasPrefix(reply, "$") {
+		t.Fatalf("Expected a bulk string, got %q", reply)
+	}
+	body := reply[strings.Index(reply, "\r\n")+2 : len(reply)-2]
+
+	fields = make(map[string]string)
+	for _, line := range strings.Split(body, "\r\n") {
+		if strings.HasPrefix(line, "# ") {
+			sections = append(sections, line[2:])
+			continue
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			fields[name] = value
+		}
+	}
+	return sections, fields
+}
+
+func TestInfoSections(t *testing.T) {
+	p := NewProcessor()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{name: "default", args: nil, expected: []string{"Server", "Clients", "Memory", "Stats", "Keyspace"}},
+		{name: "single section", args: []string{"clients"}, expected: []string{"Clients"}},
+		{name: "case insensitive", args: []string{"KEYSPACE", "Server"}, expected: []string{"Server", "Keyspace"}},
+		{name: "all", args: []string{"all"}, expected: []string{"Server", "Clients", "Memory", "Stats", "Commandstats", "Keyspace"}},
+		{name: "unknown section", args: []string{"nothing"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, _ := infoFields(t, p, tt.args...)
+			if strings.Join(sections, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected sections %v, got %v", tt.expected, sections)
+			}
+		})
+	}
+}
+
+func TestInfoStats(t *testing.T) {
+	c := clock.NewManual(time.UnixMilli(1_000_000))
+	p := NewProcessorWithOptions(Options{Clock: c})
+	client := p.NewClient()
+	p.AddClient(client)
+
+	p.ProcessCommand([]string{"SET", "key", "value"})
+	p.ProcessCommand([]string{"SET", "volatile", "value", "EX", "100"})
+	p.ProcessCommand([]string{"GET", "key"})
+	p.ProcessCommand([]string{"GET", "missing"})
+	p.ProcessCommand([]string{"GET"})
+	p.ProcessCommand([]string{"LLEN", "key"})
+	c.Advance(90 * time.Second)
+
+	_, fields := infoFields(t, p, "everything")
+	expected := map[string]string{
+		"connected_clients":          "1",
+		"blocked_clients":            "0",
+		"total_connections_received": "1",
+		"total_commands_processed":   "5",
+		"keyspace_hits":              "2",
+		"keyspace_misses":            "1",
+		"uptime_in_seconds":          "90",
+		"db0":                        "keys=2,expires=1,avg_ttl=0",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("Expected %s:%s, got %q", name, value, fields[name])
+		}
+	}
+
+	for _, prefix := range []string{"calls=2,", "rejected_calls=1,failed_calls=0"} {
+		if !strings.Contains(fields["cmdstat_get"], prefix) {
+			t.Errorf("Expected cmdstat_get to contain %q, got %q", prefix, fields["cmdstat_get"])
+		}
+	}
+	if !strings.HasSuffix(fields["cmdstat_llen"], "failed_calls=1") {
+		t.Errorf("Expected LLEN on a string to fail, got %q", fields["cmdstat_llen"])
+	}
+
+	p.RemoveClient(client)
+	p.ProcessCommand([]string{"CONFIG", "RESETSTAT"})
+	_, fields = infoFields(t, p, "stats", "clients", "commandstats")
+	// CONFIG RESETSTAT itself is counted once the counters are reset
+	if fields["connected_clients"] != "0" || fields["total_commands_processed"] != "1" || fields["keyspace_hits"] != "0" {
+		t.Errorf("Expected counters to be reset, got %v", fields)
+	}
+	if _, exists := fields["cmdstat_get"]; exists {
+		t.Errorf("Expected command stats to be reset, got %q", fields["cmdstat_get"])
+	}
+}
+
+func TestInfoBlockedClients(t *testing.T) {
+	p := NewProcessor()
+	done := make(chan string)
+	go func() {
+		done <- p.ProcessCommand([]string{"BLPOP", "queue", "0"})
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for p.ListStore.BlockedClients() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	_, fields := infoFields(t, p, "clients")
+	if fields["blocked_clients"] != "1" || fields["total_blocking_keys"] != "1" {
+		t.Errorf("Expected 1 blocked client on 1 key, got %v", fields)
+	}
+
+	p.ProcessCommand([]string{"RPUSH", "queue", "job"})
+	<-done
+	_, fields = infoFields(t, p, "clients")
+	if fields["blocked_clients"] != "0" {
+		t.Errorf("Expected no blocked client, got %q", fields["blocked_clients"])
+	}
+}
+
+func TestInfoCommandStatsExcludeBlockedTime(t *testing.T) {
+	p := NewProcessor()
+	if reply := p.ProcessCommand([]string{"BLPOP", "queue", "0.2"}); reply != "*-1\r\n" {
+		t.Fatalf("Expected BLPOP to time out, got %q", reply)
+	}
+
+	_, fields := infoFields(t, p, "commandstats")
+	var usec int64
+	for _, field := range strings.Split(fields["cmdstat_blpop"], ",") {
+		if value, ok := strings.CutPrefix(field, "usec="); ok {
+			usec, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if !strings.HasPrefix(fields["cmdstat_blpop"], "calls=1,") || usec >= 100_000 {
+		t.Errorf("Expected a single BLPOP call without the time spent blocked, got %q", fields["cmdstat_blpop"])
+	}
+}
+
+func TestInfoVerbatimUnderRESP3(t *testing.T) {
+	p := NewProcessor()
+	c := p.NewClient()
+	c.Protocol = resp.Protocol3
+
+	reply := execute(p, c, []string{"INFO", "server"})
+	if !strings.HasPrefix(reply, "=") || !strings.Contains(reply, "txt:# Server\r\n") {
+		t.Errorf("Expected a verbatim string, got %q", reply)
+	}
+}
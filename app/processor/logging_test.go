@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+func TestSlowCommandsAreLogged(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.Default()
+	cfg.SlowlogLogSlowerThan = 0
+	p := NewProcessorWithOptions(Options{
+		Config: cfg,
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+	c := p.NewClient()
+	c.Addr = "127.0.0.1:50000"
+
+	execute(p, c, []string{"SET", "key", "value"})
+	if !strings.Contains(buf.String(), `msg="Slow command"`) ||
+		!strings.Contains(buf.String(), "addr=127.0.0.1:50000 command=set") {
+		t.Errorf("Expected the command to be logged, got %q", buf.String())
+	}
+
+	buf.Reset()
+	execute(p, c, []string{"CONFIG", "SET", "slowlog-log-slower-than", "-1"})
+	execute(p, c, []string{"GET", "key"})
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be logged once disabled, got %q", buf.String())
+	}
+}
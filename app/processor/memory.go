@@ -0,0 +1,27 @@
+package processor
+
+import (
+	rtmetrics "runtime/metrics"
+)
+
+// usedMemoryMetric is the runtime metric holding the bytes allocated by heap objects, the
+// used_memory of INFO, which unlike runtime.ReadMemStats is read without stopping the world
+const usedMemoryMetric = "/memory/classes/heap/objects:bytes"
+
+// errOOM is the error replied to the commands growing the dataset once maxmemory is exceeded
+const errOOM = "OOM command not allowed when used memory > 'maxmemory'."
+
+// usedMemory returns the bytes allocated by heap objects.
+func usedMemory() uint64 {
+	samples := []rtmetrics.Sample{{Name: usedMemoryMetric}}
+	rtmetrics.Read(samples)
+	return samples[0].Value.Uint64()
+}
+
+// rejectedByMaxMemory reports whether cmd must be refused because the memory used exceeds maxmemory.
+// Keys are never evicted, so every maxmemory-policy behaves as noeviction: commands flagged
+// denyoom fail while the other commands, such as reads and deletes, still run.
+func (p *Processor) rejectedByMaxMemory(cmd *Command) bool {
+	limit := p.Config.Current().MaxMemory
+	return limit > 0 && cmd.HasFlag(FlagDenyOOM) && usedMemory() > uint64(limit)
+}
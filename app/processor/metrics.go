@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+	"github.com/codecrafters-io/redis-starter-go/app/metrics"
+)
+
+// WriteMetrics writes the server metrics in the Prometheus text format, it is called on every scrape.
+// The values are the ones reported by INFO, CONFIG RESETSTAT resets the counters of both.
+func (p *Processor) WriteMetrics(w *metrics.Writer) {
+	uptime := p.clock.Now().Sub(p.stats.startTime)
+	w.Family("redis_uptime_seconds", "Number of seconds since the server started.", metrics.Gauge)
+	w.Sample("redis_uptime_seconds", float64(uptime/time.Second))
+
+	w.Family("redis_connected_clients", "Number of client connections.", metrics.Gauge)
+	w.Sample("redis_connected_clients", float64(p.stats.connectedClients.Load()))
+	w.Family("redis_connections_received_total", "Number of connections accepted by the server.", metrics.Counter)
+	w.Sample("redis_connections_received_total", float64(p.stats.connectionsReceived.Load()))
+	w.Family("redis_rejected_connections_total", "Number of connections rejected by the server.", metrics.Counter)
+	w.Sample("redis_rejected_connections_total", float64(p.stats.rejectedConnections.Load()))
+	w.Family("redis_blocked_clients", "Number of clients blocked in a blocking command.", metrics.Gauge)
+	w.Sample("redis_blocked_clients", float64(p.ListStore.BlockedClients()))
+	w.Family("redis_blocking_keys", "Number of keys blocked clients are waiting on.", metrics.Gauge)
+	w.Sample("redis_blocking_keys", float64(p.ListStore.BlockingKeys()))
+
+	p.writeKeyspaceMetrics(w)
+	p.writeCommandMetrics(w)
+}
+
+// writeKeyspaceMetrics writes the key counts and the lookup and expiration counters.
+func (p *Processor) writeKeyspaceMetrics(w *metrics.Writer) {
+	p.Keyspace.Lock()
+	counts := make([]int, len(keyspace.Types))
+	for i, t := range keyspace.Types {
+		counts[i] = p.Keyspace.TypeLen(t)
+	}
+	expires := p.Keyspace.VolatileLen()
+	p.Keyspace.Unlock()
+
+	w.Family("redis_keys", "Number of keys by type of value.", metrics.Gauge)
+	for i, t := range keyspace.Types {
+		w.Sample("redis_keys", float64(counts[i]), metrics.Label{Name: "type", Value: string(t)})
+	}
+	w.Family("redis_keys_with_expiry", "Number of keys with a timeout.", metrics.Gauge)
+	w.Sample("redis_keys_with_expiry", float64(expires))
+
+	lookups := p.Keyspace.LookupStats()
+	w.Family("redis_keyspace_hits_total", "Number of successful lookups of keys.", metrics.Counter)
+	w.Sample("redis_keyspace_hits_total", float64(lookups.Hits))
+	w.Family("redis_keyspace_misses_total", "Number of failed lookups of keys.", metrics.Counter)
+	w.Sample("redis_keyspace_misses_total", float64(lookups.Misses))
+
+	w.Family("redis_expired_keys_total", "Number of keys removed because their timeout elapsed.", metrics.Counter)
+	w.Sample("redis_expired_keys_total", float64(p.Keyspace.ExpireStats().ExpiredKeys))
+	// Keys are never evicted, maxmemory only rejects the commands growing the dataset,
+	// the counter is exported so dashboards keep working once eviction is supported
+	w.Family("redis_evicted_keys_total", "Number of keys evicted due to the maxmemory limit.", metrics.Counter)
+	w.Sample("redis_evicted_keys_total", 0)
+}
+
+// writeCommandMetrics writes the per command counters and latency histograms,
+// skipping the commands that were never called.
+func (p *Processor) writeCommandMetrics(w *metrics.Writer) {
+	names := make([]string, 0, len(p.commands))
+	for name, cmd := range p.commands {
+		if cmd.stats.calls.Load() > 0 || cmd.stats.rejected.Load() > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	w.Family("redis_commands_processed_total", "Number of commands executed by the server.", metrics.Counter)
+	w.Sample("redis_commands_processed_total", float64(p.stats.commandsProcessed.Load()))
+
+	w.Family("redis_command_duration_seconds", "Time spent executing each command.", metrics.HistogramType)
+	for _, name := range names {
+		w.Histogram("redis_command_duration_seconds", p.commands[name].stats.latency.Snapshot(), metrics.Label{Name: "cmd", Value: name})
+	}
+	w.Family("redis_command_rejected_calls_total", "Number of calls refused before executing, e.g. for a wrong number of arguments.", metrics.Counter)
+	for _, name := range names {
+		w.Sample("redis_command_rejected_calls_total", float64(p.commands[name].stats.rejected.Load()), metrics.Label{Name: "cmd", Value: name})
+	}
+	w.Family("redis_command_failed_calls_total", "Number of calls that replied with an error.", metrics.Counter)
+	for _, name := range names {
+		w.Sample("redis_command_failed_calls_total", float64(p.commands[name].stats.failed.Load()), metrics.Label{Name: "cmd", Value: name})
+	}
+}
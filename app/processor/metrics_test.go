@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/metrics"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	p := NewProcessor()
+	p.AddClient(p.NewClient())
+	p.ProcessCommand([]string{"SET", "key", "value"})
+	p.ProcessCommand([]string{"RPUSH", "list", "a"})
+	p.ProcessCommand([]string{"GET", "key"})
+	p.ProcessCommand([]string{"GET", "missing"})
+	p.ProcessCommand([]string{"LLEN", "key"})
+	p.ProcessCommand([]string{"GET"})
+
+	var sb strings.Builder
+	w := metrics.NewWriter(&sb)
+	p.WriteMetrics(w)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+
+	for _, line := range []string{
+		"redis_connected_clients 1\n",
+		"redis_blocked_clients 0\n",
+		"redis_keys{type=\"string\"} 1\n",
+		"redis_keys{type=\"list\"} 1\n",
+		"redis_keys{type=\"stream\"} 0\n",
+		"redis_keyspace_hits_total 2\n",
+		"redis_keyspace_misses_total 1\n",
+		"redis_commands_processed_total 5\n",
+		"redis_command_duration_seconds_count{cmd=\"get\"} 2\n",
+		"redis_command_duration_seconds_bucket{cmd=\"set\",le=\"+Inf\"} 1\n",
+		"redis_command_rejected_calls_total{cmd=\"get\"} 1\n",
+		"redis_command_failed_calls_total{cmd=\"llen\"} 1\n",
+		"redis_evicted_keys_total 0\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+	if strings.Contains(out, `cmd="ping"`) {
+		t.Error("Expected commands never called to be skipped")
+	}
+}
@@ -0,0 +1,165 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// monitorBufferSize is the number of lines a monitor may lag behind before it is dropped
+const monitorBufferSize = 1024
+
+// Monitor receives a line describing every command executed by any client, see MONITOR.
+// Lines are delivered without ever blocking the executing clients: a monitor that falls
+// more than monitorBufferSize lines behind is dropped and its Done channel is closed.
+type Monitor struct {
+	// lines buffers the lines not yet sent to the monitoring client
+	lines chan string
+	// done is closed once the monitor is removed, either by RemoveMonitor or because it was too slow
+	done chan struct{}
+	// once guards closing done
+	once sync.Once
+}
+
+// Lines returns the channel the monitor lines are delivered on.
+func (m *Monitor) Lines() <-chan string {
+	return m.lines
+}
+
+// Done returns a channel closed once the monitor stops receiving lines.
+func (m *Monitor) Done() <-chan struct{} {
+	return m.done
+}
+
+// stop closes the done channel, it may be called several times.
+func (m *Monitor) stop() {
+	m.once.Do(func() { close(m.done) })
+}
+
+// monitors is the set of monitors commands are fanned out to.
+type monitors struct {
+	mutex sync.RWMutex
+	// set holds the active monitors
+	set map[*Monitor]struct{}
+	// count mirrors len(set) so Execute can skip formatting lines when nobody is listening
+	count atomic.Int64
+}
+
+// AddMonitor starts delivering the commands executed by every client to a new monitor.
+func (p *Processor) AddMonitor() *Monitor {
+	m := &Monitor{
+		lines: make(chan string, monitorBufferSize),
+		done:  make(chan struct{}),
+	}
+	p.monitors.mutex.Lock()
+	defer p.monitors.mutex.Unlock()
+	if p.monitors.set == nil {
+		p.monitors.set = make(map[*Monitor]struct{})
+	}
+	p.monitors.set[m] = struct{}{}
+	p.monitors.count.Store(int64(len(p.monitors.set)))
+	return m
+}
+
+// RemoveMonitor stops delivering lines to m.
+func (p *Processor) RemoveMonitor(m *Monitor) {
+	p.monitors.mutex.Lock()
+	defer p.monitors.mutex.Unlock()
+	delete(p.monitors.set, m)
+	p.monitors.count.Store(int64(len(p.monitors.set)))
+	m.stop()
+}
+
+// feedMonitors sends the command row executed by c to every monitor.
+// Monitors whose buffer is full are dropped rather than waited for.
+func (p *Processor) feedMonitors(c *client.Client, cmd *Command, row []string) {
+	if p.monitors.count.Load() == 0 || cmd.HasFlag(FlagAdmin) {
+		return
+	}
+	line := monitorLine(p.clock.Now(), c, redactArgs(cmd, row))
+
+	var slow []*Monitor
+	p.monitors.mutex.RLock()
+	for m := range p.monitors.set {
+		select {
+		case m.lines <- line:
+		default:
+			slow = append(slow, m)
+		}
+	}
+	p.monitors.mutex.RUnlock()
+
+	for _, m := range slow {
+		p.Logger.Info("Dropping a monitor that can't keep up with the traffic")
+		p.RemoveMonitor(m)
+	}
+}
+
+// monitorLine formats the line sent to monitors for a command.
+// Example: 1700000000.000123 [0 127.0.0.1:50000] "SET" "key" "value"
+func monitorLine(now time.Time, c *client.Client, args []string) string {
+	addr := c.Addr
+	if addr == "" {
+		addr = "internal"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strconv.FormatInt(now.Unix(), 10))
+	sb.WriteByte('.')
+	usec := strconv.Itoa(now.Nanosecond() / 1000)
+	sb.WriteString(strings.Repeat("0", 6-len(usec)) + usec)
+	sb.WriteString(" [0 " + addr + "]")
+	for _, arg := range args {
+		sb.WriteByte(' ')
+		quoteArg(&sb, arg)
+	}
+	return sb.String()
+}
+
+// quoteArg writes arg between double quotes, escaping special and non printable bytes.
+func quoteArg(sb *strings.Builder, arg string) {
+	const hex = "0123456789abcdef"
+	sb.WriteByte('"')
+	for i := 0; i < len(arg); i++ {
+		b := arg[i]
+		switch b {
+		case '\\', '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(b)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\a':
+			sb.WriteString(`\a`)
+		case '\b':
+			sb.WriteString(`\b`)
+		default:
+			if b < ' ' || b > '~' {
+				sb.WriteString(`\x`)
+				sb.WriteByte(hex[b>>4])
+				sb.WriteByte(hex[b&0xf])
+			} else {
+				sb.WriteByte(b)
+			}
+		}
+	}
+	sb.WriteByte('"')
+}
+
+// MonitorCommand implements the MONITOR command, switching the connection into monitor mode.
+// The connection receives a line for every command executed by any client from then on.
+// Example: MONITOR
+func (p *Processor) MonitorCommand(c *client.Client, w *resp.Writer, args []string) {
+	c.Lock()
+	c.Monitor = true
+	c.Unlock()
+	w.WriteSimpleString("OK")
+}
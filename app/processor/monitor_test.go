@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// nextLine returns the next line received by m, failing if none is waiting.
+func nextLine(t *testing.T, m *Monitor) string {
+	t.Helper()
+	select {
+	case line := <-m.Lines():
+		return line
+	default:
+		t.Fatal("Expected a monitor line")
+		return ""
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	cfg := config.Default()
+	cfg.RequirePass = "secret"
+	p := NewProcessorWithOptions(Options{Clock: clock.NewManual(time.UnixMicro(1700000000_000042)), Config: cfg})
+	c := p.NewClient()
+	c.Addr = "127.0.0.1:50000"
+
+	m := p.AddMonitor()
+	execute(p, c, []string{"AUTH", "secret"})
+	execute(p, c, []string{"HELLO", "3", "AUTH", "default", "secret", "SETNAME", "worker"})
+	execute(p, c, []string{"set", "key", "a \"quoted\"\nvalue\x01"})
+	execute(p, c, []string{"CONFIG", "GET", "requirepass"})
+	execute(p, c, []string{"GET"})
+	execute(p, c, []string{"NOPE"})
+
+	expected := []string{
+		`1700000000.000042 [0 127.0.0.1:50000] "AUTH" "(redacted)"`,
+		`1700000000.000042 [0 127.0.0.1:50000] "HELLO" "3" "AUTH" "(redacted)" "(redacted)" "SETNAME" "worker"`,
+		`1700000000.000042 [0 127.0.0.1:50000] "set" "key" "a \"quoted\"\nvalue\x01"`,
+	}
+	for _, want := range expected {
+		if got := nextLine(t, m); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+	// Admin commands and rejected calls are not shown
+	if len(m.Lines()) != 0 {
+		t.Errorf("Expected no other line, got %q", <-m.Lines())
+	}
+
+	p.RemoveMonitor(m)
+	select {
+	case <-m.Done():
+	default:
+		t.Error("Expected Done to be closed once the monitor is removed")
+	}
+	execute(p, c, []string{"PING"})
+	if len(m.Lines()) != 0 {
+		t.Error("Expected a removed monitor to stop receiving lines")
+	}
+}
+
+func TestSlowMonitorIsDropped(t *testing.T) {
+	p := NewProcessor()
+	slow := p.AddMonitor()
+	fast := p.AddMonitor()
+
+	for i := 0; i <= monitorBufferSize; i++ {
+		p.ProcessCommand([]string{"PING"})
+		<-fast.Lines()
+	}
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("Expected the slow monitor to be dropped")
+	}
+	select {
+	case <-fast.Done():
+		t.Error("Expected the fast monitor to keep receiving lines")
+	default:
+	}
+	if n := p.monitors.count.Load(); n != 1 {
+		t.Errorf("Expected 1 monitor left, got %d", n)
+	}
+}
+
+func TestMonitorCommand(t *testing.T) {
+	p := NewProcessor()
+	c := p.NewClient()
+	if got := execute(p, c, []string{"MONITOR"}); got != "+OK\r\n" || !c.Monitor {
+		t.Errorf("Expected MONITOR to switch the client into monitor mode, got %q", got)
+	}
+	if got := execute(p, c, []string{"MONITOR", "x"}); !strings.HasPrefix(got, "-ERR wrong number of arguments") {
+		t.Errorf("Expected an arity error, got %q", got)
+	}
+}
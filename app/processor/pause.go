@@ -0,0 +1,119 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+)
+
+// Pause modes of CLIENT PAUSE.
+const (
+	// pauseNone lets every command run
+	pauseNone = iota
+	// pauseWrite holds back the commands that may modify the keyspace
+	pauseWrite
+	// pauseAll holds back every command except CLIENT, so the pause can be lifted early
+	pauseAll
+)
+
+// clientPause holds commands back until a deadline, see CLIENT PAUSE.
+type clientPause struct {
+	mutex sync.Mutex
+	// mode is the pause mode, pauseNone when clients aren't paused
+	mode int
+	// until is when the pause ends
+	until time.Time
+	// changed is closed when the pause is lifted or changed, waking up the held commands
+	changed chan struct{}
+}
+
+// Pause holds back the commands selected by mode until the given time.
+// An ongoing pause is only ever extended: the later deadline and the stricter mode are kept.
+func (p *Processor) Pause(mode int, until time.Time) {
+	p.pause.mutex.Lock()
+	defer p.pause.mutex.Unlock()
+	if p.pause.mode != pauseNone && p.clock.Now().Before(p.pause.until) {
+		mode = max(mode, p.pause.mode)
+		if until.Before(p.pause.until) {
+			until = p.pause.until
+		}
+	}
+	p.pause.mode = mode
+	p.pause.until = until
+	p.pause.wake()
+}
+
+// Unpause lets the held commands run, see CLIENT UNPAUSE.
+func (p *Processor) Unpause() {
+	p.pause.mutex.Lock()
+	defer p.pause.mutex.Unlock()
+	p.pause.mode = pauseNone
+	p.pause.wake()
+}
+
+// wake wakes up the held commands so they check the pause again, the pause lock must be held.
+func (cp *clientPause) wake() {
+	if cp.changed != nil {
+		close(cp.changed)
+		cp.changed = nil
+	}
+}
+
+// holds reports whether mode holds cmd back.
+func holds(mode int, cmd *Command) bool {
+	switch mode {
+	case pauseAll:
+		return cmd.Name != "client"
+	case pauseWrite:
+		return cmd.HasFlag(FlagWrite)
+	}
+	return false
+}
+
+// waitUnpaused blocks until clients are no longer paused for cmd or the client is killed.
+// The client is reported as blocked while it waits.
+func (p *Processor) waitUnpaused(c *client.Client, cmd *Command) {
+	blocked := false
+	defer func() {
+		if blocked {
+			// The client interacts again once released, it must not be taken for idle meanwhile
+			c.Lock()
+			c.Blocked = false
+			c.LastInteraction = p.clock.Now()
+			c.Unlock()
+		}
+	}()
+
+	for {
+		p.pause.mutex.Lock()
+		now := p.clock.Now()
+		if !holds(p.pause.mode, cmd) || !now.Before(p.pause.until) {
+			p.pause.mutex.Unlock()
+			return
+		}
+		if p.pause.changed == nil {
+			p.pause.changed = make(chan struct{})
+		}
+		changed := p.pause.changed
+		remaining := p.pause.until.Sub(now)
+		p.pause.mutex.Unlock()
+
+		if !blocked {
+			blocked = true
+			c.Lock()
+			c.Blocked = true
+			c.Unlock()
+		}
+
+		timer := p.clock.NewTimer(remaining)
+		select {
+		case <-changed:
+		case <-timer.C():
+		case <-c.Done():
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+	}
+}
@@ -1,8 +1,16 @@
 package processor
 
 import (
-	"strings"
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/key_commands"
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 	"github.com/codecrafters-io/redis-starter-go/app/list"
 	"github.com/codecrafters-io/redis-starter-go/app/resp"
 	"github.com/codecrafters-io/redis-starter-go/app/stream"
@@ -10,7 +18,14 @@ import (
 	"github.com/codecrafters-io/redis-starter-go/app/type_commands"
 )
 
+// Version is the Redis version reported to clients.
+const Version = "7.4.0"
+
 type Processor struct {
+	// Config holds the server settings, which CONFIG SET may change at runtime
+	Config *config.Store
+	// Keyspace holds every key shared by the stores below
+	Keyspace *keyspace.Keyspace
 	// StringStore handles string-related commands
 	StringStore *string_commands.Store
 	// listStore handles list-related commands
@@ -19,60 +34,189 @@ type Processor struct {
 	StreamStore *stream.Store
 	// TypeStore handles type-related commands
 	TypeStore *type_commands.Store
+	// KeyStore handles generic commands working on keys of any type
+	KeyStore *key_commands.Store
+	// commands maps lowercase command names to their definition
+	commands map[string]*Command
+	// stats holds the server wide counters reported by INFO
+	stats *serverStats
+	// slowlog keeps the latest commands that took longer than slowlog-log-slower-than
+	slowlog *slowlog
+	// monitors receive every executed command, see MONITOR
+	monitors monitors
+	// clients holds the connected clients, see CLIENT LIST
+	clients clientRegistry
+	// pause holds commands back while clients are paused, see CLIENT PAUSE
+	pause clientPause
+	// shutdownRequests delivers the requests made by SHUTDOWN to the server
+	shutdownRequests chan ShutdownRequest
+	// shuttingDown is set once the server started shutting down
+	shuttingDown atomic.Bool
+	// Logger receives the messages of the server, such as slow commands
+	Logger *slog.Logger
+	// clock tells the time of everything but key expiry, such as idle clients and pauses,
+	// so DEBUG JUMP-TIME moving the keyspace clock can't close clients or end pauses
+	clock clock.Clock
+}
+
+// Options configures a Processor created with NewProcessorWithOptions.
+type Options struct {
+	// Clock tells the time to every store. Defaults to the system clock.
+	Clock clock.Clock
+	// Config holds the server settings. Defaults to config.Default().
+	Config *config.Config
+	// Logger receives the messages of the server. Defaults to discarding them.
+	Logger *slog.Logger
 }
 
-// NewProcessor creates a new Processor instance with initialized storage and blocking clients.
+// NewProcessor creates a new Processor instance with a shared keyspace and initialized stores.
 func NewProcessor() *Processor {
-	stringStore := string_commands.NewStore()
-	listStore := list.NewStore()
-	streamStore := stream.NewStore()
-	return &Processor{
-		StringStore: stringStore,
-		ListStore:   listStore,
-		StreamStore: streamStore,
-		TypeStore:   type_commands.NewStore(stringStore, listStore, streamStore),
+	return NewProcessorWithOptions(Options{})
+}
+
+// NewProcessorWithOptions creates a new Processor instance configured by opts.
+func NewProcessorWithOptions(opts Options) *Processor {
+	c, keyspaceClock := opts.Clock, opts.Clock
+	if c == nil {
+		c = clock.Real{}
+		// Wrap the system clock so DEBUG JUMP-TIME can move the time keys expire at
+		keyspaceClock = clock.NewOffset(c)
+	}
+
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Default()
 	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	ks := keyspace.NewWithClock(keyspaceClock)
+	p := &Processor{
+		Config:      config.NewStore(cfg),
+		Keyspace:    ks,
+		StringStore: string_commands.NewStore(ks),
+		ListStore:   list.NewStore(ks),
+		StreamStore: stream.NewStore(ks),
+		TypeStore:   type_commands.NewStore(ks),
+		KeyStore:    key_commands.NewStore(ks),
+		stats:       newServerStats(c.Now()),
+		slowlog:     newSlowlog(cfg.SlowlogMaxLen),
+		Logger:      logger,
+		clock:       c,
+
+		shutdownRequests: make(chan ShutdownRequest, 1),
+	}
+	p.registerCommands()
+	p.Config.OnChange(func(cfg *config.Config) {
+		p.slowlog.resize(cfg.SlowlogMaxLen)
+	})
+	return p
 }
 
 // ProcessCommand handles the incoming Redis command and returns the response.
+// The command runs on behalf of a fresh RESP2 client, connections use Execute instead.
 func (p *Processor) ProcessCommand(row []string) string {
-	var response string
-	response = ""
+	return resp.Record(func(w *resp.Writer) {
+		p.Execute(p.NewClient(), w, row)
+	})
+}
+
+// Execute runs a command on behalf of c and writes the reply to w,
+// encoded for the protocol c negotiated.
+func (p *Processor) Execute(c *client.Client, w *resp.Writer, row []string) {
+	w.SetProtocol(c.Protocol)
+	c.Lock()
+	c.LastInteraction = p.clock.Now()
+	c.Unlock()
+
 	if len(row) == 0 {
-		response = resp.MakeNullBulkString()
-		return response
+		w.WriteNullBulk()
+		return
 	}
 
-	command := strings.ToUpper(row[0])
-	switch command {
-	case "PING":
-		response = resp.MakeSimpleString("PONG")
-	case "ECHO":
-		response = p.StringStore.Echo(row)
-	case "SET":
-		response = p.StringStore.Set(row)
-	case "GET":
-		response = p.StringStore.Get(row)
-	case "RPUSH":
-		response = p.ListStore.RPush(row)
-	case "LRANGE":
-		response = p.ListStore.LRange(row)
-	case "LPUSH":
-		response = p.ListStore.LPush(row)
-	case "LLEN":
-		response = p.ListStore.LLen(row)
-	case "LPOP":
-		response = p.ListStore.LPop(row)
-	case "BLPOP":
-		response = p.ListStore.BLPop(row)
-	case "XADD":
-		response = p.StreamStore.XAdd(row)
-	case "XRANGE":
-		response = p.StreamStore.XRange(row)
-	case "TYPE":
-		response = p.TypeStore.Type(row)
+	cmd, exists := p.LookupCommand(row[0])
+	if !exists {
+		w.WriteError(unknownCommandError(row))
+		return
+	}
+
+	if !cmd.CheckArity(len(row)) {
+		cmd.stats.rejected.Add(1)
+		w.WriteError(wrongArityError(cmd.Name))
+		return
+	}
+
+	if !c.Authenticated && !cmd.HasFlag(FlagNoAuth) {
+		cmd.stats.rejected.Add(1)
+		w.WriteError("NOAUTH Authentication required.")
+		return
+	}
+
+	if p.rejectedByMaxMemory(cmd) {
+		cmd.stats.rejected.Add(1)
+		w.WriteError(errOOM)
+		return
+	}
+
+	c.Lock()
+	c.LastCommand = cmd.Name
+	c.Unlock()
+
+	p.waitUnpaused(c, cmd)
+	// A client killed while its command was held must not run it
+	if c.Killed() {
+		return
+	}
+	p.feedMonitors(c, cmd, row)
+
+	errors := w.Errors()
+	start := time.Now()
+	var blockedAt time.Time
+	switch {
+	case cmd.BlockingHandler != nil:
+		// The client only counts as blocked once the command has to wait, not when served right away
+		block := func() context.Context {
+			blockedAt = time.Now()
+			ctx := c.BeginBlocking()
+			// A shutdown that began before the client was blocked could not wake it up
+			if p.ShuttingDown() {
+				c.Unblock(errShuttingDown)
+			}
+			return ctx
+		}
+		cmd.BlockingHandler(block, w, row)
+		c.EndBlocking(p.clock.Now())
+	case cmd.ClientHandler != nil:
+		cmd.ClientHandler(c, w, row)
 	default:
-		response = resp.MakeSimpleString("PONG")
+		cmd.Handler(w, row)
+	}
+	duration := time.Since(start)
+	if !blockedAt.IsZero() {
+		// As in Redis, the time spent waiting is not accounted for, only the work until the client blocked
+		duration = blockedAt.Sub(start)
+	}
+	cmd.stats.record(duration, w.Errors() > errors)
+	p.stats.commandsProcessed.Add(1)
+
+	// Blocking commands spend most of their time waiting, which is not worth reporting
+	threshold := p.Config.Current().SlowlogLogSlowerThan
+	if threshold >= 0 && duration.Microseconds() >= int64(threshold) && !cmd.HasFlag(FlagBlocking) {
+		p.slowlog.add(slowlogEntry{
+			time:     p.clock.Now(),
+			duration: duration,
+			args:     slowlogArgs(redactArgs(cmd, row)),
+			addr:     c.Addr,
+			name:     c.Name,
+		})
+		p.Logger.Info("Slow command",
+			"client_id", c.ID,
+			"addr", c.Addr,
+			"command", cmd.Name,
+			"duration_us", duration.Microseconds(),
+		)
 	}
-	return response
 }
@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"testing"
+)
+
+const wrongTypeError = "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"
+
+func TestWrongTypeAcrossStores(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    [][]string
+		input    []string
+		expected string
+	}{
+		{
+			name:     "RPUSH on a string key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"RPUSH", "k", "x"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "LPUSH on a stream key",
+			setup:    [][]string{{"XADD", "k", "1-1", "f", "v"}},
+			input:    []string{"LPUSH", "k", "x"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "GET on a list key",
+			setup:    [][]string{{"RPUSH", "k", "x"}},
+			input:    []string{"GET", "k"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "LRANGE on a string key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"LRANGE", "k", "0", "-1"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "LLEN on a stream key",
+			setup:    [][]string{{"XADD", "k", "1-1", "f", "v"}},
+			input:    []string{"LLEN", "k"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "LPOP on a string key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"LPOP", "k"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "BLPOP on a string key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"BLPOP", "k", "0"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "XADD on a list key",
+			setup:    [][]string{{"RPUSH", "k", "x"}},
+			input:    []string{"XADD", "k", "1-1", "f", "v"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "XRANGE on a string key",
+			setup:    [][]string{{"SET", "k", "v"}},
+			input:    []string{"XRANGE", "k", "-", "+"},
+			expected: wrongTypeError,
+		},
+		{
+			name:     "SET overwrites a list key",
+			setup:    [][]string{{"RPUSH", "k", "x"}, {"SET", "k", "v"}},
+			input:    []string{"GET", "k"},
+			expected: "$1\r\nv\r\n",
+		},
+		{
+			name:     "TYPE reports the type of the single value",
+			setup:    [][]string{{"SET", "k", "v"}, {"RPUSH", "k", "x"}},
+			input:    []string{"TYPE", "k"},
+			expected: "+string\r\n",
+		},
+		{
+			name:     "Failed XADD does not create the key",
+			setup:    [][]string{{"XADD", "k", "0-0", "f", "v"}},
+			input:    []string{"TYPE", "k"},
+			expected: "+none\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewProcessor()
+			for _, cmd := range tt.setup {
+				processor.ProcessCommand(cmd)
+			}
+
+			result := processor.ProcessCommand(tt.input)
+			if result != tt.expected {
+				t.Errorf("ProcessCommand(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package processor
 
 import (
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 func TestDefineResponse(t *testing.T) {
@@ -15,10 +20,15 @@ func TestDefineResponse(t *testing.T) {
 			input:    []string{"PING"},
 			expected: "+PONG\r\n",
 		},
+		{
+			name:     "PING with a message",
+			input:    []string{"PING", "hi"},
+			expected: "$2\r\nhi\r\n",
+		},
 		{
 			name:     "PING with additional arguments",
 			input:    []string{"PING", "extra", "args"},
-			expected: "+PONG\r\n",
+			expected: "-ERR wrong number of arguments for 'ping' command\r\n",
 		},
 		{
 			name:     "ECHO command with single argument",
@@ -28,22 +38,37 @@ func TestDefineResponse(t *testing.T) {
 		{
 			name:     "ECHO command with multiple arguments",
 			input:    []string{"ECHO", "hello", "world", "test"},
-			expected: "$16\r\nhello world test\r\n",
+			expected: "-ERR wrong number of arguments for 'echo' command\r\n",
 		},
 		{
 			name:     "ECHO command without arguments",
 			input:    []string{"ECHO"},
-			expected: "$0\r\n\r\n",
+			expected: "-ERR wrong number of arguments for 'echo' command\r\n",
 		},
 		{
 			name:     "Unknown command",
 			input:    []string{"UNKNOWN"},
-			expected: "+PONG\r\n",
+			expected: "-ERR unknown command 'UNKNOWN', with args beginning with: \r\n",
 		},
 		{
 			name:     "Unknown command with arguments",
 			input:    []string{"UNKNOWN", "key"},
-			expected: "+PONG\r\n",
+			expected: "-ERR unknown command 'UNKNOWN', with args beginning with: 'key' \r\n",
+		},
+		{
+			name:     "Unknown command with CRLF in arguments",
+			input:    []string{"FOO", "x\r\n+INJECTED"},
+			expected: "-ERR unknown command 'FOO', with args beginning with: 'x  +INJECTED' \r\n",
+		},
+		{
+			name:     "Unknown command with long arguments",
+			input:    []string{strings.Repeat("a", 200), strings.Repeat("b", 200)},
+			expected: "-ERR unknown command '" + strings.Repeat("a", 128) + "', with args beginning with: '" + strings.Repeat("b", 128) + "' \r\n",
+		},
+		{
+			name:     "Unknown command with many arguments",
+			input:    []string{"FOO", strings.Repeat("a", 100), strings.Repeat("b", 100), "c"},
+			expected: "-ERR unknown command 'FOO', with args beginning with: '" + strings.Repeat("a", 100) + "' '" + strings.Repeat("b", 25) + "' \r\n",
 		},
 		{
 			name:     "Empty input",
@@ -89,10 +114,44 @@ func TestDefineResponseEdgeCases(t *testing.T) {
 		t.Errorf("defineResponse([\"ECHO\", \"\"]) = %q, want %q", result, expected)
 	}
 
-	// Test ECHO with spaces in arguments
-	result = processor.ProcessCommand([]string{"ECHO", "hello world", "test"})
-	expected = "$16\r\nhello world test\r\n"
+	// Test ECHO with spaces in the argument
+	result = processor.ProcessCommand([]string{"ECHO", "hello world"})
+	expected = "$11\r\nhello world\r\n"
 	if result != expected {
-		t.Errorf("defineResponse([\"ECHO\", \"hello world\", \"test\"]) = %q, want %q", result, expected)
+		t.Errorf("defineResponse([\"ECHO\", \"hello world\"]) = %q, want %q", result, expected)
+	}
+}
+
+// stalledConn blocks every write until closed, like the connection of a client that stopped reading.
+type stalledConn chan struct{}
+
+func (c stalledConn) Write(p []byte) (int, error) {
+	<-c
+	return len(p), nil
+}
+
+func TestStalledClientDoesNotBlockOthers(t *testing.T) {
+	processor := NewProcessor()
+	value := strings.Repeat("v", 1024)
+	for i := 0; i < 100; i++ {
+		processor.ProcessCommand([]string{"RPUSH", "list", value})
+	}
+
+	conn := make(stalledConn)
+	defer close(conn)
+	w := resp.NewWriter(conn)
+	done := make(chan struct{})
+	go func() {
+		processor.Execute(client.New(), w, []string{"LRANGE", "list", "0", "-1"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected LRANGE to return while its client isn't reading")
+	}
+	if result := processor.ProcessCommand([]string{"LLEN", "list"}); result != ":100\r\n" {
+		t.Errorf("LLEN = %q, want %q", result, ":100\r\n")
 	}
 }
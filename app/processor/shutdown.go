@@ -0,0 +1,91 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// errShuttingDown is the error replied by blocking commands interrupted by a shutdown
+var errShuttingDown = errors.New("UNBLOCKED server is shutting down")
+
+// ShutdownRequest asks the server to shut down, see SHUTDOWN.
+type ShutdownRequest struct {
+	// Now skips waiting for running commands to finish
+	Now bool
+}
+
+// RequestShutdown asks the server to shut down, the request is delivered on ShutdownRequests.
+// Requests made while another one is pending are dropped.
+func (p *Processor) RequestShutdown(req ShutdownRequest) {
+	select {
+	case p.shutdownRequests <- req:
+	default:
+	}
+}
+
+// ShutdownRequests returns the channel the shutdown requests made by SHUTDOWN are delivered on.
+func (p *Processor) ShutdownRequests() <-chan ShutdownRequest {
+	return p.shutdownRequests
+}
+
+// BeginShutdown prepares the processor for the server to stop: blocked clients are woken up
+// with an error, paused clients are released and new blocking commands fail right away.
+// Running commands are left to finish.
+func (p *Processor) BeginShutdown() {
+	p.shuttingDown.Store(true)
+	p.Unpause()
+	for _, c := range p.Clients() {
+		c.Unblock(errShuttingDown)
+	}
+}
+
+// ShuttingDown reports whether BeginShutdown was called, connections stop reading commands then.
+func (p *Processor) ShuttingDown() bool {
+	return p.shuttingDown.Load()
+}
+
+// Shutdown implements the SHUTDOWN command, asking the server to stop.
+// The calling client receives no reply on success, its connection is closed by the shutdown.
+// Saving the dataset is not supported: SAVE fails unless FORCE is given.
+// Example: SHUTDOWN NOSAVE NOW
+func (p *Processor) Shutdown(w *resp.Writer, args []string) {
+	var save, noSave, now, force, abort bool
+	for _, arg := range args[1:] {
+		switch strings.ToUpper(arg) {
+		case "SAVE":
+			save = true
+		case "NOSAVE":
+			noSave = true
+		case "NOW":
+			now = true
+		case "FORCE":
+			force = true
+		case "ABORT":
+			abort = true
+		default:
+			w.WriteError("ERR syntax error")
+			return
+		}
+	}
+	if (save && noSave) || (abort && len(args) > 2) {
+		w.WriteError("ERR syntax error")
+		return
+	}
+	// A shutdown starts right away and can't be interrupted once started
+	if abort {
+		w.WriteError("ERR No shutdown in progress.")
+		return
+	}
+	if save {
+		p.Logger.Warn("Error trying to save the DB, saving is not supported")
+		if !force {
+			w.WriteError("ERR Errors trying to SHUTDOWN. Check logs.")
+			return
+		}
+	}
+
+	p.Logger.Info("User requested shutdown...")
+	p.RequestShutdown(ShutdownRequest{Now: now})
+}
@@ -0,0 +1,73 @@
+package processor
+
+import "testing"
+
+func TestShutdownCommand(t *testing.T) {
+	p := NewProcessor()
+	c := p.NewClient()
+
+	errors := []struct {
+		input    []string
+		expected string
+	}{
+		{[]string{"SHUTDOWN", "LATER"}, "-ERR syntax error\r\n"},
+		{[]string{"SHUTDOWN", "SAVE", "NOSAVE"}, "-ERR syntax error\r\n"},
+		{[]string{"SHUTDOWN", "ABORT", "NOW"}, "-ERR syntax error\r\n"},
+		{[]string{"SHUTDOWN", "ABORT"}, "-ERR No shutdown in progress.\r\n"},
+		{[]string{"SHUTDOWN", "SAVE"}, "-ERR Errors trying to SHUTDOWN. Check logs.\r\n"},
+	}
+	for _, tt := range errors {
+		if got := execute(p, c, tt.input); got != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+	select {
+	case req := <-p.ShutdownRequests():
+		t.Fatalf("Expected no shutdown request after errors, got %+v", req)
+	default:
+	}
+
+	tests := []struct {
+		input []string
+		now   bool
+	}{
+		{[]string{"SHUTDOWN"}, false},
+		{[]string{"SHUTDOWN", "NOSAVE", "NOW"}, true},
+		{[]string{"shutdown", "save", "force"}, false},
+	}
+	for _, tt := range tests {
+		if got := execute(p, c, tt.input); got != "" {
+			t.Errorf("%v: expected no reply, got %q", tt.input, got)
+		}
+		select {
+		case req := <-p.ShutdownRequests():
+			if req.Now != tt.now {
+				t.Errorf("%v: expected Now=%v, got %v", tt.input, tt.now, req.Now)
+			}
+		default:
+			t.Errorf("%v: expected a shutdown request", tt.input)
+		}
+	}
+}
+
+func TestBeginShutdownWakesBlockedClients(t *testing.T) {
+	p := NewProcessor()
+	var killed bool
+	blocked := connect(p, "127.0.0.1:50000", &killed)
+
+	replies := make(chan string, 1)
+	go func() { replies <- execute(p, blocked, []string{"BLPOP", "list", "0"}) }()
+	waitBlocked(t, blocked)
+
+	p.BeginShutdown()
+	if !p.ShuttingDown() {
+		t.Error("Expected the processor to be shutting down")
+	}
+	if got := receive(t, replies); got != "-UNBLOCKED server is shutting down\r\n" {
+		t.Errorf("Expected the blocked client to be woken up with an error, got %q", got)
+	}
+	// Blocking commands started afterwards fail right away
+	if got := execute(p, blocked, []string{"BLPOP", "list", "0"}); got != "-UNBLOCKED server is shutting down\r\n" {
+		t.Errorf("Expected BLPOP to fail during a shutdown, got %q", got)
+	}
+}
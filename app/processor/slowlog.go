@@ -0,0 +1,242 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+const (
+	// slowlogMaxArgc is the number of arguments kept in a slow log entry
+	slowlogMaxArgc = 32
+	// slowlogMaxArgLen is the number of bytes kept of each argument in a slow log entry
+	slowlogMaxArgLen = 128
+	// slowlogDefaultCount is the number of entries returned by SLOWLOG GET without a count
+	slowlogDefaultCount = 10
+)
+
+// redacted replaces the sensitive arguments kept in the slow log and shown to monitors
+const redacted = "(redacted)"
+
+// slowlogEntry records a command that took longer than slowlog-log-slower-than.
+type slowlogEntry struct {
+	// id increases with every entry and is never reset, even by SLOWLOG RESET
+	id int64
+	// time is when the command ran
+	time time.Time
+	// duration is the time spent executing the command
+	duration time.Duration
+	// args is the command and its arguments, truncated by slowlogArgs
+	args []string
+	// addr is the address of the client that ran the command
+	addr string
+	// name is the name of the client that ran the command
+	name string
+}
+
+// slowlog keeps the latest slow commands in a ring buffer.
+// It is safe for concurrent use.
+type slowlog struct {
+	mutex sync.Mutex
+	// entries is the ring buffer, its length is the maximum number of entries kept
+	entries []slowlogEntry
+	// head is the index the next entry is written at
+	head int
+	// count is the number of entries in the buffer
+	count int
+	// nextID is the id of the next entry
+	nextID int64
+}
+
+// newSlowlog creates a slow log keeping up to maxLen entries.
+func newSlowlog(maxLen int) *slowlog {
+	return &slowlog{entries: make([]slowlogEntry, maxLen)}
+}
+
+// add records e, overwriting the oldest entry when the buffer is full.
+func (s *slowlog) add(e slowlogEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e.id = s.nextID
+	s.nextID++
+	if len(s.entries) == 0 {
+		return
+	}
+	s.entries[s.head] = e
+	s.head = (s.head + 1) % len(s.entries)
+	if s.count < len(s.entries) {
+		s.count++
+	}
+}
+
+// latest returns up to n entries, newest first. A negative n returns every entry.
+func (s *slowlog) latest(n int) []slowlogEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if n < 0 || n > s.count {
+		n = s.count
+	}
+	entries := make([]slowlogEntry, n)
+	for i := range entries {
+		entries[i] = s.entries[(s.head-1-i+len(s.entries))%len(s.entries)]
+	}
+	return entries
+}
+
+// length returns the number of entries in the slow log.
+func (s *slowlog) length() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+// reset removes every entry, ids keep increasing.
+func (s *slowlog) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	clear(s.entries)
+	s.head = 0
+	s.count = 0
+}
+
+// resize changes the number of entries kept, dropping the oldest ones if needed.
+func (s *slowlog) resize(maxLen int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if maxLen == len(s.entries) {
+		return
+	}
+
+	kept := min(s.count, maxLen)
+	entries := make([]slowlogEntry, maxLen)
+	// Copy the kept entries oldest first, so the newest ends right before the new head
+	for i := 0; i < kept; i++ {
+		entries[kept-1-i] = s.entries[(s.head-1-i+len(s.entries))%len(s.entries)]
+	}
+	s.entries = entries
+	s.count = kept
+	s.head = 0
+	if maxLen > 0 {
+		s.head = kept % maxLen
+	}
+}
+
+// slowlogArgs returns a copy of args small enough to be kept in the slow log.
+// Arguments past slowlogMaxArgc and bytes past slowlogMaxArgLen are replaced by a summary.
+// Example: slowlogArgs(40 args) -> 31 args + "... (9 more arguments)"
+func slowlogArgs(args []string) []string {
+	argc := min(len(args), slowlogMaxArgc)
+	kept := make([]string, argc)
+	for i := 0; i < argc; i++ {
+		if argc != len(args) && i == argc-1 {
+			kept[i] = fmt.Sprintf("... (%d more arguments)", len(args)-argc+1)
+			break
+		}
+		arg := args[i]
+		if len(arg) > slowlogMaxArgLen {
+			arg = fmt.Sprintf("%s... (%d more bytes)", arg[:slowlogMaxArgLen], len(arg)-slowlogMaxArgLen)
+		}
+		kept[i] = arg
+	}
+	return kept
+}
+
+// redactArgs returns row with the passwords given to sensitive commands redacted.
+// Example: redactArgs(config, ["CONFIG", "SET", "requirepass", "secret"]) -> ["CONFIG", "SET", "requirepass", "(redacted)"]
+// Example: redactArgs(auth, ["AUTH", "default", "secret"]) -> ["AUTH", "(redacted)", "(redacted)"]
+func redactArgs(cmd *Command, row []string) []string {
+	switch cmd.Name {
+	case "auth":
+		args := make([]string, len(row))
+		args[0] = row[0]
+		for i := 1; i < len(row); i++ {
+			args[i] = redacted
+		}
+		return args
+	case "hello":
+		args := append([]string(nil), row...)
+		for i := 2; i < len(args); i++ {
+			if strings.EqualFold(args[i], "AUTH") {
+				for j := i + 1; j <= i+2 && j < len(args); j++ {
+					args[j] = redacted
+				}
+				i += 2
+			}
+		}
+		return args
+	case "config":
+		if len(row) < 2 || !strings.EqualFold(row[1], "SET") {
+			return row
+		}
+		args := append([]string(nil), row...)
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.EqualFold(args[i], "requirepass") {
+				args[i+1] = redacted
+			}
+		}
+		return args
+	}
+	return row
+}
+
+// Slowlog implements the SLOWLOG command, reporting the commands that took longer than
+// slowlog-log-slower-than microseconds to execute.
+// Example: SLOWLOG GET 5
+// Example: SLOWLOG LEN
+// Example: SLOWLOG RESET
+func (p *Processor) Slowlog(w *resp.Writer, args []string) {
+	subcommand := strings.ToUpper(args[1])
+	switch subcommand {
+	case "GET":
+		if len(args) > 3 {
+			w.WriteError(wrongArityError("slowlog|get"))
+			return
+		}
+		count := slowlogDefaultCount
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil {
+				w.WriteError("ERR value is not an integer or out of range")
+				return
+			}
+			if n < -1 {
+				w.WriteError("ERR count should be greater than or equal to -1")
+				return
+			}
+			count = n
+		}
+
+		entries := p.slowlog.latest(count)
+		w.WriteArrayHeader(len(entries))
+		for _, e := range entries {
+			w.WriteArrayHeader(6)
+			w.WriteInteger(e.id)
+			w.WriteInteger(e.time.Unix())
+			w.WriteInteger(e.duration.Microseconds())
+			w.WriteBulkArray(e.args)
+			w.WriteBulk(e.addr)
+			w.WriteBulk(e.name)
+		}
+	case "LEN":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("slowlog|len"))
+			return
+		}
+		w.WriteInteger(int64(p.slowlog.length()))
+	case "RESET":
+		if len(args) != 2 {
+			w.WriteError(wrongArityError("slowlog|reset"))
+			return
+		}
+		p.slowlog.reset()
+		w.WriteSimpleString("OK")
+	default:
+		w.WriteError("ERR unknown subcommand '" + resp.ErrorArg(args[1]) + "'. Try SLOWLOG HELP.")
+	}
+}
@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// slowlogIDs returns the ids of the entries in the slow log, newest first.
+func slowlogIDs(s *slowlog) []int64 {
+	ids := []int64{}
+	for _, e := range s.latest(-1) {
+		ids = append(ids, e.id)
+	}
+	return ids
+}
+
+func TestSlowlogRing(t *testing.T) {
+	s := newSlowlog(3)
+	for i := 0; i < 5; i++ {
+		s.add(slowlogEntry{})
+	}
+	if ids := slowlogIDs(s); !reflect.DeepEqual(ids, []int64{4, 3, 2}) {
+		t.Errorf("Expected the 3 newest entries, got %v", ids)
+	}
+	if ids := s.latest(2); len(ids) != 2 || ids[0].id != 4 {
+		t.Errorf("Expected the 2 newest entries, got %v", ids)
+	}
+
+	s.resize(2)
+	if ids := slowlogIDs(s); !reflect.DeepEqual(ids, []int64{4, 3}) {
+		t.Errorf("Expected shrinking to keep the newest entries, got %v", ids)
+	}
+	s.resize(4)
+	s.add(slowlogEntry{})
+	if ids := slowlogIDs(s); !reflect.DeepEqual(ids, []int64{5, 4, 3}) {
+		t.Errorf("Expected growing to keep every entry, got %v", ids)
+	}
+
+	s.reset()
+	s.add(slowlogEntry{})
+	if ids := slowlogIDs(s); !reflect.DeepEqual(ids, []int64{6}) {
+		t.Errorf("Expected ids to keep increasing after a reset, got %v", ids)
+	}
+
+	s.resize(0)
+	s.add(slowlogEntry{})
+	if s.length() != 0 {
+		t.Errorf("Expected a zero length slow log to stay empty, got %d entries", s.length())
+	}
+}
+
+func TestSlowlogArgs(t *testing.T) {
+	args := []string{"RPUSH", "list", strings.Repeat("x", 130)}
+	for i := 0; i < 40; i++ {
+		args = append(args, strconv.Itoa(i))
+	}
+
+	kept := slowlogArgs(args)
+	if len(kept) != slowlogMaxArgc {
+		t.Fatalf("Expected %d arguments, got %d", slowlogMaxArgc, len(kept))
+	}
+	if kept[2] != strings.Repeat("x", 128)+"... (2 more bytes)" {
+		t.Errorf("Expected the long argument to be truncated, got %q", kept[2])
+	}
+	if kept[31] != "... (12 more arguments)" {
+		t.Errorf("Expected a summary of the missing arguments, got %q", kept[31])
+	}
+
+	short := []string{"GET", "key"}
+	if kept := slowlogArgs(short); !reflect.DeepEqual(kept, short) {
+		t.Errorf("Expected short commands to be kept as is, got %q", kept)
+	}
+}
+
+func TestSlowlogCommand(t *testing.T) {
+	cfg := config.Default()
+	cfg.SlowlogLogSlowerThan = 0
+	p := NewProcessorWithOptions(Options{Clock: clock.NewManual(time.Unix(1700000000, 0)), Config: cfg})
+	c := p.NewClient()
+	c.Addr = "127.0.0.1:50000"
+	c.Name = "worker"
+
+	execute(p, c, []string{"SET", "key", "value"})
+	reply := execute(p, c, []string{"SLOWLOG", "GET", "1"})
+	prefix := "*1\r\n*6\r\n:0\r\n:1700000000\r\n:"
+	suffix := "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n$15\r\n127.0.0.1:50000\r\n$6\r\nworker\r\n"
+	if !strings.HasPrefix(reply, prefix) || !strings.HasSuffix(reply, suffix) {
+		t.Errorf("Unexpected SLOWLOG GET reply %q", reply)
+	}
+
+	steps := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		// The previous SLOWLOG GET was recorded too
+		{name: "SLOWLOG LEN", input: []string{"SLOWLOG", "LEN"}, expected: ":2\r\n"},
+		{name: "SLOWLOG RESET", input: []string{"slowlog", "reset"}, expected: "+OK\r\n"},
+		{name: "SLOWLOG LEN after RESET", input: []string{"SLOWLOG", "LEN"}, expected: ":1\r\n"},
+		{name: "SLOWLOG GET with a negative count", input: []string{"SLOWLOG", "GET", "-2"}, expected: "-ERR count should be greater than or equal to -1\r\n"},
+		{name: "SLOWLOG GET with a non integer count", input: []string{"SLOWLOG", "GET", "x"}, expected: "-ERR value is not an integer or out of range\r\n"},
+		{name: "SLOWLOG LEN with arguments", input: []string{"SLOWLOG", "LEN", "1"}, expected: "-ERR wrong number of arguments for 'slowlog|len' command\r\n"},
+		{name: "unknown subcommand", input: []string{"SLOWLOG", "FOO"}, expected: "-ERR unknown subcommand 'FOO'. Try SLOWLOG HELP.\r\n"},
+		{name: "shrink with CONFIG SET", input: []string{"CONFIG", "SET", "slowlog-max-len", "1"}, expected: "+OK\r\n"},
+		{name: "SLOWLOG LEN after shrinking", input: []string{"SLOWLOG", "LEN"}, expected: ":1\r\n"},
+	}
+	for _, step := range steps {
+		if got := execute(p, c, step.input); got != step.expected {
+			t.Errorf("%s: expected %q, got %q", step.name, step.expected, got)
+		}
+	}
+}
+
+func TestSlowlogRedactsSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.SlowlogLogSlowerThan = 0
+	p := NewProcessorWithOptions(Options{Config: cfg})
+	c := p.NewClient()
+
+	execute(p, c, []string{"AUTH", "default", "secret"})
+	execute(p, c, []string{"HELLO", "2", "AUTH", "default", "secret"})
+	execute(p, c, []string{"CONFIG", "SET", "slowlog-max-len", "10", "requirepass", "secret"})
+	execute(p, c, []string{"AUTH", "secret"})
+	reply := execute(p, c, []string{"SLOWLOG", "GET"})
+	if strings.Contains(reply, "secret") {
+		t.Errorf("Expected the passwords to be redacted, got %q", reply)
+	}
+	if !strings.Contains(reply, "$15\r\nslowlog-max-len\r\n$2\r\n10\r\n$11\r\nrequirepass\r\n$10\r\n(redacted)\r\n") {
+		t.Errorf("Expected only the requirepass value to be redacted, got %q", reply)
+	}
+}
+
+func TestSlowlogThreshold(t *testing.T) {
+	p := NewProcessor()
+	p.ProcessCommand([]string{"SET", "key", "value"})
+	if got := p.ProcessCommand([]string{"SLOWLOG", "LEN"}); got != ":0\r\n" {
+		t.Errorf("Expected fast commands to be skipped, got %q", got)
+	}
+}
@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/metrics"
+)
+
+// commandStats counts the calls to a single command.
+// Counters are updated atomically as commands run concurrently on every connection.
+type commandStats struct {
+	// calls is the number of times the handler ran
+	calls atomic.Int64
+	// usec is the total time spent in the handler, in microseconds, not counting the time blocked
+	usec atomic.Int64
+	// rejected is the number of calls refused before running, e.g. on a wrong arity
+	rejected atomic.Int64
+	// failed is the number of calls whose handler replied with an error
+	failed atomic.Int64
+	// latency is the distribution of the time spent in the handler, not counting the time blocked,
+	// exported as a metric
+	latency *metrics.Histogram
+}
+
+// record accounts for a call to the handler that lasted d.
+func (s *commandStats) record(d time.Duration, failed bool) {
+	s.calls.Add(1)
+	s.usec.Add(d.Microseconds())
+	s.latency.Observe(d)
+	if failed {
+		s.failed.Add(1)
+	}
+}
+
+// reset sets the counters back to zero.
+func (s *commandStats) reset() {
+	s.calls.Store(0)
+	s.usec.Store(0)
+	s.rejected.Store(0)
+	s.failed.Store(0)
+	s.latency.Reset()
+}
+
+// serverStats holds the server wide counters reported by INFO.
+type serverStats struct {
+	// startTime is when the processor was created, used to compute the uptime
+	startTime time.Time
+	// runID identifies this run of the server
+	runID string
+	// connectedClients is the number of clients currently connected
+	connectedClients atomic.Int64
+	// connectionsReceived is the number of connections accepted since the start
+	connectionsReceived atomic.Int64
+	// rejectedConnections is the number of connections refused since the start
+	rejectedConnections atomic.Int64
+	// commandsProcessed is the number of commands executed since the start
+	commandsProcessed atomic.Int64
+	// peakMemory is the highest heap usage seen by INFO, in bytes
+	peakMemory atomic.Uint64
+}
+
+// newServerStats creates the counters of a server started at start.
+func newServerStats(start time.Time) *serverStats {
+	return &serverStats{
+		startTime: start,
+		runID:     newRunID(),
+	}
+}
+
+// reset sets the counters that CONFIG RESETSTAT clears back to zero.
+// Gauges such as the number of connected clients are kept.
+func (s *serverStats) reset() {
+	s.connectionsReceived.Store(0)
+	s.rejectedConnections.Store(0)
+	s.commandsProcessed.Store(0)
+}
+
+// observeMemory records used as the heap usage and returns the peak seen so far.
+func (s *serverStats) observeMemory(used uint64) uint64 {
+	for {
+		peak := s.peakMemory.Load()
+		if used <= peak {
+			return peak
+		}
+		if s.peakMemory.CompareAndSwap(peak, used) {
+			return used
+		}
+	}
+}
+
+// newRunID returns 40 random hex characters.
+func newRunID() string {
+	var b [20]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// RejectConnection records that a connection was refused.
+func (p *Processor) RejectConnection() {
+	p.stats.rejectedConnections.Add(1)
+}
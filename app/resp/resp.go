@@ -5,14 +5,14 @@ import (
 	"strings"
 )
 
-// MakeError creates a RESP error message.
+// MakeError creates a RESP error message, CR and LF in msg are replaced with spaces.
 func MakeError(msg string) string {
-	return fmt.Sprintf("-%s\r\n", msg)
+	return fmt.Sprintf("-%s\r\n", singleLine(msg))
 }
 
-// MakeSimpleString creates a RESP simple string.
+// MakeSimpleString creates a RESP simple string, CR and LF in s are replaced with spaces.
 func MakeSimpleString(s string) string {
-	return fmt.Sprintf("+%s\r\n", s)
+	return fmt.Sprintf("+%s\r\n", singleLine(s))
 }
 
 // MakeBulkString creates a RESP bulk string.
@@ -59,3 +59,14 @@ func MakeRESPArray(items []string) string {
 	}
 	return sb.String()
 }
+
+// maxErrorArgLen is the most bytes of a client argument echoed in an error, as in Redis
+const maxErrorArgLen = 128
+
+// ErrorArg cuts a client argument echoed in an error to its first 128 bytes.
+func ErrorArg(s string) string {
+	if len(s) > maxErrorArgLen {
+		return s[:maxErrorArgLen]
+	}
+	return s
+}
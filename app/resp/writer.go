@@ -0,0 +1,204 @@
+package resp
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BufferSize is the amount of buffered replies past which connections flush between
+// pipelined commands, rather than once the whole pipeline is answered
+const BufferSize = 16 * 1024
+
+// Protocol versions understood by Writer.
+const (
+	// Protocol2 encodes replies with the RESP2 types only
+	Protocol2 = 2
+	// Protocol3 encodes replies with the richer RESP3 types
+	Protocol3 = 3
+)
+
+// Writer encodes replies into a buffer sent to the stream by Flush. Replies never reach
+// the stream before Flush, so a client that stops reading can't block a handler, e.g. while
+// it holds the keyspace lock. Aggregate replies are written as a header announcing the
+// number of elements followed by each element.
+// Methods do not report write errors, a failed Flush makes every later one fail as well.
+type Writer struct {
+	// w buffers the encoded replies until Flush
+	w bytes.Buffer
+	// out is the stream the replies are sent to
+	out io.Writer
+	// err is the error of the first failed Flush
+	err error
+	// protocol selects between RESP2 and RESP3 encodings
+	protocol int
+	// scratch is reused to format numbers without allocating
+	scratch [32]byte
+	// errors counts the error replies written so far
+	errors int64
+}
+
+// NewWriter creates a Writer speaking RESP2 on top of w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		out:      w,
+		protocol: Protocol2,
+	}
+}
+
+// Record runs fn against a Writer backed by memory and returns the RESP it wrote.
+// Example: Record(func(w *Writer) { w.WriteInteger(1) }) -> ":1\r\n"
+func Record(fn func(w *Writer)) string {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	fn(w)
+	_ = w.Flush()
+	return buf.String()
+}
+
+// SetProtocol selects the RESP version used by the following replies.
+func (w *Writer) SetProtocol(protocol int) {
+	w.protocol = protocol
+}
+
+// Protocol returns the RESP version used to encode replies.
+func (w *Writer) Protocol() int {
+	return w.protocol
+}
+
+// Flush writes the buffered replies to the underlying stream.
+func (w *Writer) Flush() error {
+	if w.err == nil && w.w.Len() > 0 {
+		_, w.err = w.out.Write(w.w.Bytes())
+	}
+	w.w.Reset()
+	return w.err
+}
+
+// Buffered returns the number of bytes waiting to be flushed.
+func (w *Writer) Buffered() int {
+	return w.w.Len()
+}
+
+// Errors returns the number of error replies written since the Writer was created.
+func (w *Writer) Errors() int64 {
+	return w.errors
+}
+
+// WriteSimpleString writes a simple string, CR and LF in s are replaced with spaces.
+func (w *Writer) WriteSimpleString(s string) {
+	w.writeLine('+', s)
+}
+
+// WriteError writes an error, msg should start with an error code such as "ERR".
+func (w *Writer) WriteError(msg string) {
+	w.errors++
+	w.writeLine('-', msg)
+}
+
+// WriteInteger writes an integer.
+func (w *Writer) WriteInteger(n int64) {
+	w.writeHeader(':', n)
+}
+
+// WriteBulk writes a binary safe bulk string.
+func (w *Writer) WriteBulk(s string) {
+	w.writeHeader('$', int64(len(s)))
+	_, _ = w.w.WriteString(s)
+	_, _ = w.w.WriteString("\r\n")
+}
+
+// WriteBulkArray writes an array of bulk strings.
+func (w *Writer) WriteBulkArray(items []string) {
+	w.WriteArrayHeader(len(items))
+	for _, item := range items {
+		w.WriteBulk(item)
+	}
+}
+
+// WriteNullBulk writes the reply for a missing string: a null bulk string in RESP2 and a null in RESP3.
+func (w *Writer) WriteNullBulk() {
+	if w.protocol == Protocol3 {
+		_, _ = w.w.WriteString("_\r\n")
+		return
+	}
+	_, _ = w.w.WriteString("$-1\r\n")
+}
+
+// WriteNullArray writes the reply for a missing aggregate: a null array in RESP2 and a null in RESP3.
+func (w *Writer) WriteNullArray() {
+	if w.protocol == Protocol3 {
+		_, _ = w.w.WriteString("_\r\n")
+		return
+	}
+	_, _ = w.w.WriteString("*-1\r\n")
+}
+
+// WriteArrayHeader announces an array of n elements, which must be written next.
+func (w *Writer) WriteArrayHeader(n int) {
+	w.writeHeader('*', int64(n))
+}
+
+// WriteMapHeader announces a map of n key/value pairs, which must be written next alternating keys and values.
+// RESP2 clients receive a flat array of 2*n elements.
+func (w *Writer) WriteMapHeader(n int) {
+	if w.protocol == Protocol3 {
+		w.writeHeader('%', int64(n))
+		return
+	}
+	w.writeHeader('*', int64(2*n))
+}
+
+// WriteSetHeader announces a set of n elements, which must be written next.
+// RESP2 clients receive an array.
+func (w *Writer) WriteSetHeader(n int) {
+	if w.protocol == Protocol3 {
+		w.writeHeader('~', int64(n))
+		return
+	}
+	w.writeHeader('*', int64(n))
+}
+
+// WriteVerbatim writes a text with a three letter format such as "txt" or "mkd".
+// RESP2 clients receive the text as a bulk string.
+func (w *Writer) WriteVerbatim(format, s string) {
+	if w.protocol != Protocol3 {
+		w.WriteBulk(s)
+		return
+	}
+	w.writeHeader('=', int64(len(format)+1+len(s)))
+	_, _ = w.w.WriteString(format)
+	_ = w.w.WriteByte(':')
+	_, _ = w.w.WriteString(s)
+	_, _ = w.w.WriteString("\r\n")
+}
+
+// writeLine writes a type prefix followed by s and CRLF.
+func (w *Writer) writeLine(prefix byte, s string) {
+	_ = w.w.WriteByte(prefix)
+	_, _ = w.w.WriteString(singleLine(s))
+	_, _ = w.w.WriteString("\r\n")
+}
+
+// singleLine replaces CR and LF in s with spaces, so a line reply can't end early and be
+// followed by forged replies, e.g. when an error echoes the arguments of the client.
+func singleLine(s string) string {
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// writeHeader writes a type prefix followed by n and CRLF.
+func (w *Writer) writeHeader(prefix byte, n int64) {
+	buf := append(w.scratch[:0], prefix)
+	buf = strconv.AppendInt(buf, n, 10)
+	buf = append(buf, '\r', '\n')
+	_, _ = w.w.Write(buf)
+}
@@ -0,0 +1,288 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer)
+		resp2 string
+		resp3 string
+	}{
+		{
+			name:  "Simple string",
+			write: func(w *Writer) { w.WriteSimpleString("OK") },
+			resp2: "+OK\r\n",
+			resp3: "+OK\r\n",
+		},
+		{
+			name:  "Error",
+			write: func(w *Writer) { w.WriteError("ERR oops") },
+			resp2: "-ERR oops\r\n",
+			resp3: "-ERR oops\r\n",
+		},
+		{
+			name:  "Error with CRLF",
+			write: func(w *Writer) { w.WriteError("ERR unknown command 'x\r\n+INJECTED'") },
+			resp2: "-ERR unknown command 'x  +INJECTED'\r\n",
+			resp3: "-ERR unknown command 'x  +INJECTED'\r\n",
+		},
+		{
+			name:  "Simple string with LF",
+			write: func(w *Writer) { w.WriteSimpleString("a\nb") },
+			resp2: "+a b\r\n",
+			resp3: "+a b\r\n",
+		},
+		{
+			name:  "Integer",
+			write: func(w *Writer) { w.WriteInteger(-42) },
+			resp2: ":-42\r\n",
+			resp3: ":-42\r\n",
+		},
+		{
+			name:  "Bulk string",
+			write: func(w *Writer) { w.WriteBulk("a\r\nb") },
+			resp2: "$4\r\na\r\nb\r\n",
+			resp3: "$4\r\na\r\nb\r\n",
+		},
+		{
+			name:  "Bulk array",
+			write: func(w *Writer) { w.WriteBulkArray([]string{"Hello", "World"}) },
+			resp2: "*2\r\n$5\r\nHello\r\n$5\r\nWorld\r\n",
+			resp3: "*2\r\n$5\r\nHello\r\n$5\r\nWorld\r\n",
+		},
+		{
+			name:  "Null bulk string",
+			write: func(w *Writer) { w.WriteNullBulk() },
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		{
+			name:  "Null array",
+			write: func(w *Writer) { w.WriteNullArray() },
+			resp2: "*-1\r\n",
+			resp3: "_\r\n",
+		},
+		{
+			name: "Map",
+			write: func(w *Writer) {
+				w.WriteMapHeader(1)
+				w.WriteBulk("key")
+				w.WriteInteger(1)
+			},
+			resp2: "*2\r\n$3\r\nkey\r\n:1\r\n",
+			resp3: "%1\r\n$3\r\nkey\r\n:1\r\n",
+		},
+		{
+			name: "Set",
+			write: func(w *Writer) {
+				w.WriteSetHeader(1)
+				w.WriteBulk("a")
+			},
+			resp2: "*1\r\n$1\r\na\r\n",
+			resp3: "~1\r\n$1\r\na\r\n",
+		},
+		{
+			name:  "Verbatim string",
+			write: func(w *Writer) { w.WriteVerbatim("txt", "Some string") },
+			resp2: "$11\r\nSome string\r\n",
+			resp3: "=15\r\ntxt:Some string\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Record(tt.write)
+			if result != tt.resp2 {
+				t.Errorf("RESP2: got %q; want %q", result, tt.resp2)
+			}
+
+			result = Record(func(w *Writer) {
+				w.SetProtocol(Protocol3)
+				tt.write(w)
+			})
+			if result != tt.resp3 {
+				t.Errorf("RESP3: got %q; want %q", result, tt.resp3)
+			}
+		})
+	}
+}
+
+func TestWriterMatchesMakeFunctions(t *testing.T) {
+	items := []string{"", "a", "hello world", "line\r\nbreak"}
+
+	expected := MakeError("ERR x") + MakeSimpleString("OK") + MakeInteger(7) +
+		MakeBulkString("bulk") + MakeNullBulkString() + MakeNullArray() + MakeArray(items)
+	result := Record(func(w *Writer) {
+		w.WriteError("ERR x")
+		w.WriteSimpleString("OK")
+		w.WriteInteger(7)
+		w.WriteBulk("bulk")
+		w.WriteNullBulk()
+		w.WriteNullArray()
+		w.WriteBulkArray(items)
+	})
+	if result != expected {
+		t.Errorf("got %q; want %q", result, expected)
+	}
+}
+
+// failingWriter rejects every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestWriterReportsErrorsOnFlush(t *testing.T) {
+	w := NewWriter(failingWriter{})
+	w.WriteSimpleString("OK")
+	if err := w.Flush(); err == nil {
+		t.Fatal("Expected Flush to report the write error")
+	}
+
+	// Errors are sticky, later replies are dropped
+	w.WriteSimpleString("OK")
+	if err := w.Flush(); err == nil {
+		t.Error("Expected the error to be reported again")
+	}
+}
+
+func TestWriterLargeReply(t *testing.T) {
+	// A reply larger than BufferSize only reaches the stream on Flush, so a client that
+	// stops reading can't block the handler writing it
+	var sink countingWriter
+	w := NewWriter(&sink)
+	for i := 0; i < 10000; i++ {
+		w.WriteBulk("element")
+	}
+	if sink.n != 0 || w.Buffered() < BufferSize {
+		t.Errorf("Expected the reply to be buffered until Flush, %d bytes were written", sink.n)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sink.n != 10000*len("$7\r\nelement\r\n") {
+		t.Errorf("Expected %d bytes, got %d", 10000*len("$7\r\nelement\r\n"), sink.n)
+	}
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += len(p)
+	return len(p), nil
+}
+
+// benchmarkItems returns n elements like those of a large LRANGE reply.
+func benchmarkItems(n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = "element-" + strconv.Itoa(i)
+	}
+	return items
+}
+
+// BenchmarkArrayReply compares building a large array reply as a string, then writing it,
+// with streaming it through a Writer.
+func BenchmarkArrayReply(b *testing.B) {
+	items := benchmarkItems(10000)
+
+	b.Run("Make", func(b *testing.B) {
+		bw := bufio.NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = bw.WriteString(MakeArray(items))
+			_ = bw.Flush()
+		}
+	})
+
+	b.Run("Writer", func(b *testing.B) {
+		w := NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			w.WriteArrayHeader(len(items))
+			for _, item := range items {
+				w.WriteBulk(item)
+			}
+			_ = w.Flush()
+		}
+	})
+}
+
+// BenchmarkNestedReply compares nested replies shaped like XRANGE entries.
+func BenchmarkNestedReply(b *testing.B) {
+	ids := benchmarkItems(1000)
+	fields := []string{"temperature", "21", "humidity", "40"}
+
+	b.Run("Make", func(b *testing.B) {
+		bw := bufio.NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			entries := make([]string, 0, len(ids))
+			for _, id := range ids {
+				entries = append(entries, MakeRESPArray([]string{MakeBulkString(id), MakeArray(fields)}))
+			}
+			_, _ = bw.WriteString(MakeRESPArray(entries))
+			_ = bw.Flush()
+		}
+	})
+
+	b.Run("Writer", func(b *testing.B) {
+		w := NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			w.WriteArrayHeader(len(ids))
+			for _, id := range ids {
+				w.WriteArrayHeader(2)
+				w.WriteBulk(id)
+				w.WriteBulkArray(fields)
+			}
+			_ = w.Flush()
+		}
+	})
+}
+
+// BenchmarkSmallReplies compares the many small replies of a pipeline.
+func BenchmarkSmallReplies(b *testing.B) {
+	b.Run("Make", func(b *testing.B) {
+		bw := bufio.NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = bw.WriteString(MakeSimpleString("OK"))
+			_, _ = bw.WriteString(MakeInteger(i))
+			_, _ = bw.WriteString(MakeBulkString("value"))
+		}
+		_ = bw.Flush()
+	})
+
+	b.Run("Writer", func(b *testing.B) {
+		w := NewWriter(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			w.WriteSimpleString("OK")
+			w.WriteInteger(int64(i))
+			w.WriteBulk("value")
+		}
+		_ = w.Flush()
+	})
+}
+
+func TestWriterCountsErrors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	w.WriteSimpleString("OK")
+	w.WriteError("ERR first")
+	w.WriteError("WRONGTYPE second")
+	if w.Errors() != 2 {
+		t.Errorf("Expected 2 errors, got %d", w.Errors())
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/logging"
+	"github.com/codecrafters-io/redis-starter-go/app/parser"
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+// connections tracks the open connections so a shutdown can close them.
+type connections struct {
+	mutex sync.Mutex
+	// set holds the open connections
+	set map[net.Conn]struct{}
+	// closing is set once the server started shutting down, new connections are refused then
+	closing bool
+	// wg counts the connections still being served
+	wg sync.WaitGroup
+}
+
+// add starts tracking conn and reports whether it may be served.
+func (cs *connections) add(conn net.Conn) bool {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.closing {
+		return false
+	}
+	if cs.set == nil {
+		cs.set = make(map[net.Conn]struct{})
+	}
+	cs.set[conn] = struct{}{}
+	cs.wg.Add(1)
+	return true
+}
+
+// remove stops tracking conn once it's no longer served.
+func (cs *connections) remove(conn net.Conn) {
+	cs.mutex.Lock()
+	delete(cs.set, conn)
+	cs.mutex.Unlock()
+	cs.wg.Done()
+}
+
+// interrupt refuses new connections and interrupts the pending reads of the open ones,
+// so connections waiting for a command stop while running commands still get their reply.
+func (cs *connections) interrupt() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.closing = true
+	for conn := range cs.set {
+		_ = conn.SetReadDeadline(time.Now())
+	}
+}
+
+// closeAll closes every open connection, replies being written are lost.
+func (cs *connections) closeAll() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	for conn := range cs.set {
+		_ = conn.Close()
+	}
+}
+
+// wait waits until every connection stopped being served or ctx is done,
+// and reports whether they all did.
+func (cs *connections) wait(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		cs.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// handleConnection serves the commands sent on conn until the client or the server closes it.
+func (s *Server) handleConnection(conn net.Conn) {
+	c := s.Processor.NewClient()
+	c.Addr = conn.RemoteAddr().String()
+	c.LocalAddr = conn.LocalAddr().String()
+	if conn.LocalAddr().Network() == "unix" {
+		// Unix socket peers are unnamed, both ends are reported as the socket path like Redis does
+		c.UnixSocket = true
+		c.Addr = conn.LocalAddr().String() + ":0"
+		c.LocalAddr = c.Addr
+	}
+	c.SetCloser(conn.Close)
+	log := s.Processor.Logger.With("client_id", c.ID, "addr", c.Addr)
+
+	if err := s.Processor.AddClient(c); err != nil {
+		logging.Verbose(log, "Rejected connection", "error", err)
+		writer := resp.NewWriter(conn)
+		writer.WriteError(err.Error())
+		_ = writer.Flush()
+		_ = conn.Close()
+		return
+	}
+	logging.Verbose(log, "Accepted connection")
+	setKeepAlive(conn, s.Processor.Config.Current().TCPKeepAlive, log)
+	defer func() {
+		s.Processor.RemoveClient(c)
+		if err := conn.Close(); err != nil {
+			logging.Verbose(log, "Error closing connection", "error", err)
+		}
+	}()
+
+	reader := parser.NewReader(conn)
+	writer := resp.NewWriter(conn)
+
+	for {
+		inputStrings, err := reader.ReadCommand()
+		if s.Processor.ShuttingDown() {
+			// Deliver the replies of the commands that ran before the shutdown
+			_ = writer.Flush()
+			logging.Verbose(log, "Closing connection for shutdown")
+			return
+		}
+		if err != nil {
+			var protocolErr *parser.ProtocolError
+			if errors.As(err, &protocolErr) {
+				logging.Verbose(log, "Protocol error", "error", protocolErr.Error(), "fatal", protocolErr.Fatal())
+				// Tell the client what went wrong, then keep serving it if the stream is still intact
+				writer.WriteError("ERR " + protocolErr.Error())
+				_ = writer.Flush()
+				if !protocolErr.Fatal() {
+					continue
+				}
+				return
+			}
+			if errors.Is(err, io.EOF) {
+				logging.Verbose(log, "Client closed connection")
+			} else {
+				logging.Verbose(log, "Error reading from client", "error", err)
+			}
+			return
+		}
+
+		// Empty requests, such as a blank inline line, are silently ignored
+		if len(inputStrings) == 0 {
+			continue
+		}
+
+		// Replies to the commands pipelined before a blocking command must not wait for it
+		if cmd, exists := s.Processor.LookupCommand(inputStrings[0]); exists && cmd.HasFlag(processor.FlagBlocking) && writer.Buffered() > 0 {
+			if err := writer.Flush(); err != nil {
+				logging.Verbose(log, "Error writing to client", "error", err)
+				return
+			}
+		}
+
+		c.Lock()
+		c.QueryBuffer = reader.Buffered()
+		c.OutputBuffer = writer.Buffered()
+		c.Unlock()
+
+		s.Processor.Execute(c, writer, inputStrings)
+
+		// CLIENT KILL closes the connection of the killed client, or flags the caller to be closed once answered
+		if c.CloseAfterReply || c.Killed() {
+			_ = writer.Flush()
+			logging.Verbose(log, "Client killed")
+			return
+		}
+
+		if c.Monitor {
+			serveMonitor(s.Processor, c, reader, writer, log)
+			return
+		}
+
+		// Flush once all pipelined commands that already arrived have been answered,
+		// or earlier when their replies pile up
+		if reader.Buffered() == 0 || writer.Buffered() >= resp.BufferSize {
+			if err := writer.Flush(); err != nil {
+				logging.Verbose(log, "Error writing to client", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// setKeepAlive enables TCP keepalive probes on conn every period seconds, or disables them if period is 0.
+// As in Redis, unanswered probes are retried every third of the period, three times, before the
+// connection is considered dead.
+func setKeepAlive(conn net.Conn, period int, log *slog.Logger) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return
+	}
+	var err error
+	if period > 0 {
+		interval := max(time.Duration(period)*time.Second/3, time.Second)
+		err = tcpConn.SetKeepAliveConfig(net.KeepAliveConfig{
+			Enable:   true,
+			Idle:     time.Duration(period) * time.Second,
+			Interval: interval,
+			Count:    3,
+		})
+	} else {
+		err = tcpConn.SetKeepAlive(false)
+	}
+	if err != nil {
+		logging.Verbose(log, "Error configuring TCP keepalive", "error", err)
+	}
+}
+
+// serveMonitor streams the commands executed by every client to a connection in monitor mode,
+// until the client disconnects or is dropped for not keeping up.
+// Commands sent by the monitoring client keep being executed.
+func serveMonitor(proc *processor.Processor, c *client.Client, reader *parser.Reader, writer *resp.Writer, log *slog.Logger) {
+	m := proc.AddMonitor()
+	defer proc.RemoveMonitor(m)
+
+	// Acknowledge MONITOR and anything pipelined before it
+	if err := writer.Flush(); err != nil {
+		logging.Verbose(log, "Error writing to client", "error", err)
+		return
+	}
+
+	// Commands are read in the background so lines can be written while waiting for input
+	commands := make(chan []string)
+	readErr := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			row, err := reader.ReadCommand()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case commands <- row:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case line := <-m.Lines():
+			writer.WriteSimpleString(line)
+		case row := <-commands:
+			if len(row) > 0 {
+				proc.Execute(c, writer, row)
+			}
+		case err := <-readErr:
+			var protocolErr *parser.ProtocolError
+			if errors.As(err, &protocolErr) {
+				writer.WriteError("ERR " + protocolErr.Error())
+				_ = writer.Flush()
+			}
+			logging.Verbose(log, "Monitor disconnected", "error", err)
+			return
+		case <-m.Done():
+			logging.Verbose(log, "Monitor dropped for not keeping up")
+			_ = writer.Flush()
+			return
+		}
+
+		// Batch the lines that are already waiting into a single write
+		if len(m.Lines()) == 0 {
+			if err := writer.Flush(); err != nil {
+				logging.Verbose(log, "Error writing to client", "error", err)
+				return
+			}
+		}
+	}
+}
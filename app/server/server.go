@@ -0,0 +1,416 @@
+// Package server serves the Redis protocol over network listeners.
+// It is used by the redis-server binary and can be embedded, e.g. to run isolated
+// in-process instances in integration tests.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/clock"
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+	"github.com/codecrafters-io/redis-starter-go/app/metrics"
+	"github.com/codecrafters-io/redis-starter-go/app/processor"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe once Shutdown was called.
+var ErrServerClosed = errors.New("server: Server closed")
+
+// ErrNoListeners is returned by ListenAndServe when port is 0 and neither tls-port nor
+// unixsocket is set.
+var ErrNoListeners = errors.New("server: configured to not listen anywhere")
+
+// Server serves the commands of the clients connecting to its listeners.
+type Server struct {
+	// Processor executes the commands of every client
+	Processor *processor.Processor
+
+	// logger receives the messages of the server
+	logger *slog.Logger
+	// mutex protects listeners and metrics
+	mutex sync.Mutex
+	// listeners are the listeners being served
+	listeners []net.Listener
+	// addr is the address reported by Addr
+	addr net.Addr
+	// metrics serves the Prometheus metrics, nil unless ListenAndServe enabled it
+	metrics *http.Server
+	// tlsAddr is the address reported by TLSAddr
+	tlsAddr net.Addr
+	// tlsConfig holds the settings of the TLS listeners, replaced when CONFIG SET changes them
+	tlsConfig atomic.Pointer[tls.Config]
+	// conns tracks the open connections
+	conns connections
+	// startOnce guards starting the background jobs
+	startOnce sync.Once
+	// stopJobs stops the background jobs, such as the active expire cycle
+	stopJobs []func()
+	// inShutdown is set once Shutdown was called
+	inShutdown atomic.Bool
+	// shutdownOnce guards the shutdown sequence
+	shutdownOnce sync.Once
+	// shutdownErr is the result of the shutdown sequence
+	shutdownErr error
+	// done is closed once the shutdown sequence completed
+	done chan struct{}
+}
+
+// Options configures a Server created with NewWithOptions.
+type Options struct {
+	// Config holds the server settings. Defaults to config.Default().
+	Config *config.Config
+	// Clock tells the time to the server. Defaults to the system clock.
+	Clock clock.Clock
+	// Logger receives the messages of the server. Defaults to discarding them.
+	Logger *slog.Logger
+}
+
+// New creates a server configured by cfg, which defaults to config.Default() when nil.
+func New(cfg *config.Config) *Server {
+	return NewWithOptions(Options{Config: cfg})
+}
+
+// NewWithOptions creates a server configured by opts.
+func NewWithOptions(opts Options) *Server {
+	proc := processor.NewProcessorWithOptions(processor.Options{Config: opts.Config, Clock: opts.Clock, Logger: opts.Logger})
+	s := &Server{
+		Processor: proc,
+		logger:    proc.Logger,
+		done:      make(chan struct{}),
+	}
+	proc.Config.OnChange(s.reloadTLS)
+	go s.handleShutdownRequests()
+	return s
+}
+
+// ListenAndServe listens on every address of the bind setting unless port is 0, with TLS
+// when tls-port is set, on the unix socket when unixsocket is set and on the metrics address
+// when metrics-port is set, and serves them until the server is shut down.
+// It always returns a non-nil error, ErrServerClosed after Shutdown.
+func (s *Server) ListenAndServe() error {
+	cfg := s.Processor.Config.Current()
+	if len(cfg.Addrs()) == 0 && len(cfg.TLSAddrs()) == 0 && cfg.UnixSocket == "" {
+		return ErrNoListeners
+	}
+
+	var plain, secure []net.Listener
+	closeAll := func() {
+		closeListeners(plain)
+		closeListeners(secure)
+	}
+	for _, addr := range cfg.Addrs() {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		plain = append(plain, l)
+	}
+
+	if tlsAddrs := cfg.TLSAddrs(); len(tlsAddrs) > 0 {
+		// Invalid certificates are reported before listening
+		tlsConfig, err := cfg.TLSConfig()
+		if err != nil {
+			closeAll()
+			return err
+		}
+		s.tlsConfig.Store(tlsConfig)
+		for _, addr := range tlsAddrs {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				closeAll()
+				return err
+			}
+			secure = append(secure, l)
+		}
+	}
+
+	if cfg.UnixSocket != "" {
+		l, err := listenUnix(cfg.Path(cfg.UnixSocket), cfg.UnixSocketPerm)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		plain = append(plain, l)
+	}
+
+	if addr := cfg.MetricsAddr(); addr != "" {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		s.serveMetrics(l)
+	}
+
+	// The listeners are served concurrently, the first ones are reported whichever starts first
+	s.mutex.Lock()
+	if len(plain) > 0 {
+		s.addr = plain[0].Addr()
+	}
+	if len(secure) > 0 {
+		s.tlsAddr = secure[0].Addr()
+	}
+	s.mutex.Unlock()
+
+	errs := make(chan error, len(plain)+len(secure))
+	for _, l := range plain {
+		go func() { errs <- s.Serve(l) }()
+	}
+	for _, l := range secure {
+		go func() { errs <- s.ServeTLS(l) }()
+	}
+	// Every listener stops at once on shutdown, otherwise report the first failure
+	err := <-errs
+	if !errors.Is(err, ErrServerClosed) {
+		closeAll()
+	}
+	return err
+}
+
+// Serve accepts the connections on l and serves them until the server is shut down.
+// Serve always returns a non-nil error and closes l. After Shutdown it waits for the
+// shutdown to complete and returns ErrServerClosed.
+// The first call starts the background jobs, such as the active expire cycle.
+func (s *Server) Serve(l net.Listener) error {
+	return s.serve(l, false)
+}
+
+// ServeTLS accepts TLS connections on l and serves them like Serve. The certificates are
+// set by the tls-* settings and loaded again on CONFIG SET, see reloadTLS.
+func (s *Server) ServeTLS(l net.Listener) error {
+	if s.tlsConfig.Load() == nil {
+		tlsConfig, err := s.Processor.Config.Current().TLSConfig()
+		if err != nil {
+			_ = l.Close()
+			return err
+		}
+		s.tlsConfig.CompareAndSwap(nil, tlsConfig)
+	}
+	// Every handshake uses the settings in effect, so reloaded certificates apply to new connections
+	return s.serve(tls.NewListener(l, &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return s.tlsConfig.Load(), nil
+		},
+	}), true)
+}
+
+// serve implements Serve and ServeTLS, secure tells whether l accepts TLS connections.
+func (s *Server) serve(l net.Listener, secure bool) error {
+	if !s.trackListener(l, secure) {
+		_ = l.Close()
+		<-s.done
+		return ErrServerClosed
+	}
+	s.startJobs()
+	s.logger.Info("Ready to accept connections", "addr", l.Addr().String(), "tls", secure)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.inShutdown.Load() {
+				<-s.done
+				return ErrServerClosed
+			}
+			_ = l.Close()
+			return err
+		}
+		if !s.conns.add(conn) {
+			_ = conn.Close()
+			continue
+		}
+
+		go func() {
+			defer s.conns.remove(conn)
+			s.handleConnection(conn)
+		}()
+	}
+}
+
+// Addr returns the address of the first plaintext listener served, TCP before the unix socket
+// with ListenAndServe. It is nil until the server is listening, or when port is 0 and
+// unixsocket is not set.
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.addr
+}
+
+// TLSAddr returns the address of the first TLS listener served, nil until the server is
+// listening with TLS.
+func (s *Server) TLSAddr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.tlsAddr
+}
+
+// reloadTLS loads the certificates again after every CONFIG SET once TLS is in use, so setting
+// tls-cert-file to its current value picks up a certificate renewed in place.
+// The config store already rejected the changes leaving the certificates invalid.
+func (s *Server) reloadTLS(cfg *config.Config) {
+	if s.tlsConfig.Load() == nil {
+		return
+	}
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		s.logger.Warn("Failed to reload the TLS configuration", "error", err)
+		return
+	}
+	s.tlsConfig.Store(tlsConfig)
+}
+
+// Shutdown stops the server: listeners are closed, blocked clients are woken up with an error,
+// then running commands may finish until ctx is done, when the remaining connections are closed
+// and the context error is returned. Saving the dataset is not supported, so nothing is persisted.
+// Shutdown may be called several times, every call waits for the first one to complete.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = s.shutdown(ctx)
+		close(s.done)
+	})
+	<-s.done
+	return s.shutdownErr
+}
+
+// shutdown runs the shutdown sequence described by Shutdown.
+func (s *Server) shutdown(ctx context.Context) error {
+	s.inShutdown.Store(true)
+
+	s.mutex.Lock()
+	closeListeners(s.listeners)
+	metricsServer := s.metrics
+	s.mutex.Unlock()
+
+	s.Processor.BeginShutdown()
+	s.conns.interrupt()
+
+	var err error
+	if !s.conns.wait(ctx) {
+		err = ctx.Err()
+		s.conns.closeAll()
+		// The connections stop as soon as their command returns, which blocked clients already did
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.conns.wait(closeCtx)
+	}
+
+	// Waits for the jobs being started, if any, and prevents starting them afterwards
+	s.startOnce.Do(func() {})
+	for _, stop := range s.stopJobs {
+		stop()
+	}
+	if metricsServer != nil {
+		// The metrics requests are short, they are not worth waiting for
+		_ = metricsServer.Close()
+	}
+	return err
+}
+
+// handleShutdownRequests shuts the server down when a client runs SHUTDOWN.
+// Running commands get up to shutdown-timeout seconds to finish, or none with SHUTDOWN NOW.
+func (s *Server) handleShutdownRequests() {
+	select {
+	case req := <-s.Processor.ShutdownRequests():
+		timeout := time.Duration(s.Processor.Config.Current().ShutdownTimeout) * time.Second
+		if req.Now {
+			timeout = 0
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil && !req.Now {
+			s.logger.Warn("Closed the connections of commands still running after the shutdown timeout")
+		}
+	case <-s.done:
+	}
+}
+
+// trackListener records l so Shutdown closes it, and reports whether it may be served.
+// secure tells whether l accepts TLS connections.
+func (s *Server) trackListener(l net.Listener, secure bool) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.inShutdown.Load() {
+		return false
+	}
+	s.listeners = append(s.listeners, l)
+	switch {
+	case secure && s.tlsAddr == nil:
+		s.tlsAddr = l.Addr()
+	case !secure && s.addr == nil:
+		s.addr = l.Addr()
+	}
+	return true
+}
+
+// startJobs starts the background jobs of the server the first time it serves a listener.
+func (s *Server) startJobs() {
+	s.startOnce.Do(func() {
+		s.stopJobs = []func(){
+			// Reclaim expired keys that are never accessed again
+			s.Processor.Keyspace.StartActiveExpire(keyspace.ActiveExpireInterval),
+			// Close the connections idle for longer than the timeout setting
+			s.Processor.StartIdleTimeout(processor.IdleCheckInterval),
+		}
+	})
+}
+
+// serveMetrics serves the Prometheus metrics over HTTP on l until the server is shut down.
+func (s *Server) serveMetrics(l net.Listener) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(s.Processor.WriteMetrics))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.inShutdown.Load() {
+		_ = l.Close()
+		return
+	}
+	s.metrics = server
+
+	s.logger.Info("Serving metrics", "addr", l.Addr().String())
+	go func() {
+		if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Warn("Error serving metrics", "error", err)
+		}
+	}()
+}
+
+// listenUnix listens on the unix socket at path, restricting its permissions to perm unless 0.
+// A socket file left over by a previous run is replaced, other files are kept.
+// The socket file is removed once the listener is closed.
+func listenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if perm != 0 {
+		if err := os.Chmod(path, perm); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// closeListeners closes every listener of ls.
+func closeListeners(ls []net.Listener) {
+	for _, l := range ls {
+		_ = l.Close()
+	}
+}
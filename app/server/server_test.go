@@ -0,0 +1,312 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// startServer serves a new server on a random local port and shuts it down at the end of the test.
+// It returns the server and the channel Serve's result is sent on.
+func startServer(t *testing.T, cfg *config.Config) (*Server, <-chan error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(cfg)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(l) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+
+	waitListening(t, srv)
+	return srv, served
+}
+
+// waitListening waits until srv serves a listener, failing after a second.
+func waitListening(t *testing.T, srv *Server) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for srv.Addr() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the server to listen")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// dial connects to srv, the connection is closed at the end of the test.
+func dial(t *testing.T, srv *Server) net.Conn {
+	t.Helper()
+	addr := srv.Addr()
+	conn, err := net.Dial(addr.Network(), addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// send writes args to conn as a RESP array of bulk strings.
+func send(t *testing.T, conn net.Conn, args ...string) {
+	t.Helper()
+	var sb strings.Builder
+	sb.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		sb.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := conn.Write([]byte(sb.String())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expectReply reads len(want) bytes from conn and fails unless they equal want.
+func expectReply(t *testing.T, conn net.Conn, want string) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("Expected %q, got %q and %v", want, got, err)
+	}
+	if string(got) != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
+
+// expectClosed fails unless the server closes conn without sending anything else.
+func expectClosed(t *testing.T, conn net.Conn) {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != io.EOF {
+		t.Fatalf("Expected the connection to be closed, got %q and %v", buf[:n], err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	srv, _ := startServer(t, nil)
+	conn := dial(t, srv)
+
+	send(t, conn, "PING")
+	expectReply(t, conn, "+PONG\r\n")
+	send(t, conn, "SET", "key", "value")
+	expectReply(t, conn, "+OK\r\n")
+	send(t, conn, "GET", "key")
+	expectReply(t, conn, "$5\r\nvalue\r\n")
+}
+
+func TestInstancesAreIsolated(t *testing.T) {
+	first, _ := startServer(t, nil)
+	second, _ := startServer(t, nil)
+	if first.Addr().String() == second.Addr().String() {
+		t.Fatal("Expected the servers to listen on different addresses")
+	}
+
+	conn := dial(t, first)
+	send(t, conn, "SET", "key", "value")
+	expectReply(t, conn, "+OK\r\n")
+
+	conn = dial(t, second)
+	send(t, conn, "GET", "key")
+	expectReply(t, conn, "$-1\r\n")
+}
+
+func TestListenAndServe(t *testing.T) {
+	// Port 0 disables plaintext TCP, the server only listens on the unix socket
+	cfg := config.Default()
+	cfg.Port = 0
+	cfg.UnixSocket = filepath.Join(t.TempDir(), "redis.sock")
+	srv := New(cfg)
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+	waitListening(t, srv)
+	if network := srv.Addr().Network(); network != "unix" {
+		t.Errorf("Expected no TCP listener with port 0, got a %s listener", network)
+	}
+
+	conn := dial(t, srv)
+	send(t, conn, "PING")
+	expectReply(t, conn, "+PONG\r\n")
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+}
+
+func TestListenAndServeWithoutListeners(t *testing.T) {
+	cfg := config.Default()
+	cfg.Port = 0
+	if err := New(cfg).ListenAndServe(); !errors.Is(err, ErrNoListeners) {
+		t.Errorf("Expected ErrNoListeners, got %v", err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	srv, served := startServer(t, nil)
+	idle := dial(t, srv)
+	blocked := dial(t, srv)
+	send(t, idle, "PING")
+	expectReply(t, idle, "+PONG\r\n")
+	send(t, blocked, "BLPOP", "list", "0")
+
+	deadline := time.Now().Add(time.Second)
+	for srv.Processor.ListStore.BlockedClients() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected BLPOP to block")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+
+	expectReply(t, blocked, "-UNBLOCKED server is shutting down\r\n")
+	expectClosed(t, blocked)
+	expectClosed(t, idle)
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+	if _, err := net.Dial("tcp", srv.Addr().String()); err == nil {
+		t.Error("Expected the listener to be closed")
+	}
+}
+
+func TestShutdownCommand(t *testing.T) {
+	srv, served := startServer(t, nil)
+	conn := dial(t, srv)
+
+	send(t, conn, "SHUTDOWN", "NOSAVE")
+	expectClosed(t, conn)
+	select {
+	case err := <-served:
+		if !errors.Is(err, ErrServerClosed) {
+			t.Errorf("Expected ErrServerClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected SHUTDOWN to stop the server")
+	}
+}
+
+func TestMaxClients(t *testing.T) {
+	cfg := config.Default()
+	cfg.MaxClients = 1
+	srv, _ := startServer(t, cfg)
+
+	first := dial(t, srv)
+	send(t, first, "PING")
+	expectReply(t, first, "+PONG\r\n")
+
+	second := dial(t, srv)
+	expectReply(t, second, "-ERR max number of clients reached\r\n")
+	expectClosed(t, second)
+}
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.sock")
+	cfg := config.Default()
+	cfg.Port = 0
+	cfg.UnixSocket = path
+	cfg.UnixSocketPerm = 0o700
+	srv := New(cfg)
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+	waitListening(t, srv)
+
+	var conn net.Conn
+	deadline := time.Now().Add(time.Second)
+	for {
+		var err error
+		if conn, err = net.Dial("unix", path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the unix socket to accept connections: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	defer conn.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("Expected the socket permissions to be 700, got %o", perm)
+	}
+
+	send(t, conn, "PING")
+	expectReply(t, conn, "+PONG\r\n")
+	send(t, conn, "CLIENT", "INFO")
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 512)
+	n, _ := conn.Read(buf)
+	if reply := string(buf[:n]); !strings.Contains(reply, "addr="+path+":0 laddr="+path+":0 ") || !strings.Contains(reply, "flags=U ") {
+		t.Errorf("Expected CLIENT INFO to report a unix socket connection, got %q", reply)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket file to be removed, got %v", err)
+	}
+}
+
+func TestUnixSocketRelativePath(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.Port = 0
+	cfg.Dir = dir
+	cfg.UnixSocket = "redis.sock"
+	srv := New(cfg)
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+	waitListening(t, srv)
+
+	// The socket is created inside dir, without changing the working directory
+	if _, err := os.Stat(filepath.Join(dir, "redis.sock")); err != nil {
+		t.Fatalf("Expected the socket file inside dir, got %v", err)
+	}
+
+	// Changing dir must not leave the socket file behind
+	conn, err := net.Dial("unix", filepath.Join(dir, "redis.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	send(t, conn, "CONFIG", "SET", "dir", t.TempDir())
+	expectReply(t, conn, "+OK\r\n")
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "redis.sock")); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/config"
+)
+
+// testCA signs the certificates used by the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	// file is the PEM file holding the CA certificate
+	file string
+}
+
+// newTestCA creates a self-signed CA, written to dir.
+func newTestCA(t *testing.T, dir string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "ca.crt")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// issue creates a certificate for name signed by ca, and writes it and its key to dir.
+// It returns the paths of the certificate and key files.
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+// pool returns a pool trusting ca.
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// writePEM writes der to file as a PEM block of the given type.
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSServer serves a new server on a plaintext and a TLS listener, using a certificate
+// issued by ca and verifying client certificates according to authClients.
+func startTLSServer(t *testing.T, ca *testCA, dir, authClients string) *Server {
+	t.Helper()
+	certFile, keyFile := ca.issue(t, dir, "server", x509.ExtKeyUsageServerAuth)
+	cfg := config.Default()
+	// The TLS listener is served below, tls-port only enables the checks of CONFIG SET
+	cfg.TLSPort = 6380
+	cfg.TLSCertFile = certFile
+	cfg.TLSKeyFile = keyFile
+	cfg.TLSCACertFile = ca.file
+	cfg.TLSAuthClients = authClients
+
+	srv, _ := startServer(t, cfg)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeTLS(l) }()
+	t.Cleanup(func() {
+		_ = srv.Shutdown(t.Context())
+		<-served
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for srv.TLSAddr() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the server to listen with TLS")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return srv
+}
+
+// dialTLS connects to the TLS listener of srv, presenting the certificates given.
+func dialTLS(t *testing.T, srv *Server, ca *testCA, certs ...tls.Certificate) (*tls.Conn, error) {
+	t.Helper()
+	conn, err := tls.Dial("tcp", srv.TLSAddr().String(), &tls.Config{
+		RootCAs:      ca.pool(),
+		Certificates: certs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if err := conn.Handshake(); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// loadKeyPair loads a certificate and its key, failing the test on error.
+func loadKeyPair(t *testing.T, certFile, keyFile string) tls.Certificate {
+	t.Helper()
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestTLSMutualAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	srv := startTLSServer(t, ca, dir, "yes")
+	clientCertFile, clientKeyFile := ca.issue(t, dir, "client", x509.ExtKeyUsageClientAuth)
+	clientCert := loadKeyPair(t, clientCertFile, clientKeyFile)
+
+	conn, err := dialTLS(t, srv, ca, clientCert)
+	if err != nil {
+		t.Fatalf("Expected the handshake to succeed, got %v", err)
+	}
+	send(t, conn, "PING")
+	expectReply(t, conn, "+PONG\r\n")
+
+	// Clients without a certificate are refused, with TLS 1.3 they only notice once they read
+	if conn, err := dialTLS(t, srv, ca); err == nil {
+		send(t, conn, "PING")
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Read(make([]byte, 16)); err == nil {
+			t.Error("Expected a client without certificate to be refused")
+		}
+	}
+
+	// The plaintext listener keeps working
+	plain := dial(t, srv)
+	send(t, plain, "PING")
+	expectReply(t, plain, "+PONG\r\n")
+}
+
+func TestTLSWithoutClientAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	srv := startTLSServer(t, ca, dir, "no")
+
+	conn, err := dialTLS(t, srv, ca)
+	if err != nil {
+		t.Fatalf("Expected the handshake to succeed, got %v", err)
+	}
+	send(t, conn, "SET", "key", "value")
+	expectReply(t, conn, "+OK\r\n")
+}
+
+func TestTLSReloadOnConfigSet(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	srv := startTLSServer(t, ca, dir, "no")
+	plain := dial(t, srv)
+
+	// Files that don't load are rejected and the certificate in use is kept
+	send(t, plain, "CONFIG", "SET", "tls-cert-file", filepath.Join(dir, "missing.crt"))
+	expectReply(t, plain, "-ERR CONFIG SET failed (possibly related to argument 'tls-cert-file') - failed to load the certificate: ")
+	_ = plain.SetReadDeadline(time.Now().Add(time.Second))
+	_, _ = plain.Read(make([]byte, 512))
+	if got := serverName(t, srv, ca); got != "server" {
+		t.Errorf("Expected the original certificate to be served, got %q", got)
+	}
+
+	certFile, keyFile := ca.issue(t, dir, "renewed", x509.ExtKeyUsageServerAuth)
+	send(t, plain, "CONFIG", "SET", "tls-cert-file", certFile, "tls-key-file", keyFile)
+	expectReply(t, plain, "+OK\r\n")
+	if got := serverName(t, srv, ca); got != "renewed" {
+		t.Errorf("Expected the renewed certificate to be served, got %q", got)
+	}
+}
+
+// serverName returns the common name of the certificate served by the TLS listener of srv.
+func serverName(t *testing.T, srv *Server, ca *testCA) string {
+	t.Helper()
+	conn, err := dialTLS(t, srv, ca)
+	if err != nil {
+		t.Fatalf("Expected the handshake to succeed, got %v", err)
+	}
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
@@ -1,10 +1,15 @@
 package stream
 
+import (
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+)
+
 // HasKey checks if a stream exists at the given key.
 // Example: HasKey("mystream") returns true if "mystream" exists
 func (s *Store) HasKey(key string) bool {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	_, exists := s.storage[key]
-	return exists
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
+
+	item, exists := s.keyspace.Lookup(key)
+	return exists && item.Type == keyspace.TypeStream
 }
@@ -2,10 +2,12 @@ package stream
 
 import (
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestHasKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Test non-existent stream
 	if store.HasKey("nonexistent") {
@@ -13,7 +15,7 @@ func TestHasKey(t *testing.T) {
 	}
 
 	// Add a stream entry
-	store.XAdd([]string{"XADD", "mystream", "0-1", "field", "value"})
+	call(store.XAdd, []string{"XADD", "mystream", "0-1", "field", "value"})
 
 	// Test existing stream
 	if !store.HasKey("mystream") {
@@ -1,7 +1,7 @@
 package stream
 
 import (
-	"sync"
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 type Entry struct {
@@ -17,15 +17,58 @@ type Stream struct {
 }
 
 type Store struct {
-	// storage maps stream keys to their corresponding streams
-	storage map[string]*Stream
-	// mutex protects concurrent access to the storage map
-	mutex sync.Mutex
+	// keyspace holds the values of all keys, stream values are stored as *Stream
+	keyspace *keyspace.Keyspace
 }
 
-// NewStore creates a new Store instance with initialized storage.
-func NewStore() *Store {
+// NewStore creates a new Store instance backed by the given keyspace.
+func NewStore(ks *keyspace.Keyspace) *Store {
 	return &Store{
-		storage: make(map[string]*Stream),
+		keyspace: ks,
 	}
 }
+
+// lookupStream returns the stream stored at key, or nil if the key doesn't exist.
+// Returns keyspace.ErrWrongType if the key holds another type of value.
+// The keyspace lock must be held.
+func (s *Store) lookupStream(key string) (*Stream, error) {
+	item, err := s.keyspace.LookupType(key, keyspace.TypeStream)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return item.Value.(*Stream), nil
+}
+
+// lookupStreamRead is lookupStream for commands that only read the stream, counting keyspace hits and misses.
+// The keyspace lock must be held.
+func (s *Store) lookupStreamRead(key string) (*Stream, error) {
+	item, err := s.keyspace.LookupTypeRead(key, keyspace.TypeStream)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	return item.Value.(*Stream), nil
+}
+
+// Clone returns a deep copy of the stream, used by COPY.
+func (st *Stream) Clone() *Stream {
+	clone := &Stream{
+		tree: NewRadixTree(),
+	}
+
+	start, _ := ParseRangeID("-", true)
+	end, _ := ParseRangeID("+", false)
+	for _, entry := range st.tree.Range(start, end) {
+		fields := make(map[string]string, len(entry.Fields))
+		for k, v := range entry.Fields {
+			fields[k] = v
+		}
+
+		key, _ := IDToKey(entry.ID)
+		clone.tree.Insert(key, &Entry{
+			ID:     entry.ID,
+			Fields: fields,
+		})
+	}
+
+	return clone
+}
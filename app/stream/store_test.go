@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
+	"github.com/codecrafters-io/redis-starter-go/app/resp"
+)
+
+func TestStreamClone(t *testing.T) {
+	store := NewStore(keyspace.New())
+	call(store.XAdd, []string{"XADD", "s", "1-1", "f", "v"})
+	call(store.XAdd, []string{"XADD", "s", "2-1", "g", "w"})
+
+	store.keyspace.Lock()
+	original, _ := store.lookupStream("s")
+	clone := original.Clone()
+	store.keyspace.Unlock()
+
+	if clone.tree.Len() != 2 {
+		t.Fatalf("Expected clone with 2 entries, got %d", clone.tree.Len())
+	}
+
+	// Changing the clone must not affect the original
+	clone.tree.First().Fields["f"] = "changed"
+	if original.tree.First().Fields["f"] != "v" {
+		t.Errorf("Expected original entry to be unchanged, got %q", original.tree.First().Fields["f"])
+	}
+	if clone.tree.Last().ID != "2-1" {
+		t.Errorf("Expected last cloned ID 2-1, got %q", clone.tree.Last().ID)
+	}
+}
+
+// call runs a handler against an in-memory writer and returns the reply it wrote.
+func call(handler func(*resp.Writer, []string), args []string) string {
+	return resp.Record(func(w *resp.Writer) { handler(w, args) })
+}
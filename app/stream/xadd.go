@@ -4,22 +4,24 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 // XAdd appends an entry to a stream and returns the entry ID.
 // Example: XAdd(["XADD", "mystream", "0-1", "temperature", "36", "humidity", "95"])
-func (s *Store) XAdd(args []string) string {
+func (s *Store) XAdd(w *resp.Writer, args []string) {
 	// XADD requires at least: command, key, ID, and one field-value pair
 	if len(args) < 5 {
-		return resp.MakeError("ERR wrong number of arguments for 'xadd' command")
+		w.WriteError("ERR wrong number of arguments for 'xadd' command")
+		return
 	}
 
 	// Field-value pairs must come in pairs (even number of arguments after ID)
 	if (len(args)-3)%2 != 0 {
-		return resp.MakeError("ERR wrong number of arguments for 'xadd' command")
+		w.WriteError("ERR wrong number of arguments for 'xadd' command")
+		return
 	}
 
 	key := args[1]
@@ -33,19 +35,21 @@ func (s *Store) XAdd(args []string) string {
 		fields[fieldName] = fieldValue
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	// Create stream if it doesn't exist
-	stream, exists := s.storage[key]
-	if !exists {
+	stream, err := s.lookupStream(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
+	}
+	// The stream is only created once the new ID has been validated
+	if stream == nil {
 		stream = &Stream{
 			tree: NewRadixTree(),
 		}
-		s.storage[key] = stream
 	}
 
-	// Get the last entry ID
 	// Get the last entry ID
 	lastID := ""
 	if lastEntry := stream.tree.Last(); lastEntry != nil {
@@ -54,10 +58,22 @@ func (s *Store) XAdd(args []string) string {
 
 	// Handle auto-generated ID: *
 	if entryID == "*" {
-		msTime := time.Now().UnixMilli()
+		msTime := s.keyspace.Now()
+		// Never go below the last ID, even if the clock went backwards
+		if lastID != "" {
+			lastMsTime, _, err := ParseID(lastID)
+			if err != nil {
+				w.WriteError(err.Error())
+				return
+			}
+			if lastMsTime > msTime {
+				msTime = lastMsTime
+			}
+		}
 		seqNum, err := GenerateSequence(msTime, lastID)
 		if err != nil {
-			return resp.MakeError(err.Error())
+			w.WriteError(err.Error())
+			return
 		}
 		entryID = fmt.Sprintf("%d-%d", msTime, seqNum)
 	} else if strings.HasSuffix(entryID, "-*") {
@@ -65,18 +81,21 @@ func (s *Store) XAdd(args []string) string {
 		timePart := strings.TrimSuffix(entryID, "-*")
 		msTime, err := strconv.ParseInt(timePart, 10, 64)
 		if err != nil {
-			return resp.MakeError("ERR value is not an integer or out of range")
+			w.WriteError("ERR value is not an integer or out of range")
+			return
 		}
 		seqNum, err := GenerateSequence(msTime, lastID)
 		if err != nil {
-			return resp.MakeError(err.Error())
+			w.WriteError(err.Error())
+			return
 		}
 		entryID = fmt.Sprintf("%d-%d", msTime, seqNum)
 	}
 
 	// Validate the new entry ID
 	if err := ValidateID(entryID, lastID); err != nil {
-		return resp.MakeError(err.Error())
+		w.WriteError(err.Error())
+		return
 	}
 
 	// Create the entry
@@ -85,14 +104,20 @@ func (s *Store) XAdd(args []string) string {
 		Fields: fields,
 	}
 
-	// Append entry to the stream
 	// Append entry to the stream
 	keyStr, err := IDToKey(entryID)
 	if err != nil {
-		return resp.MakeError(err.Error())
+		w.WriteError(err.Error())
+		return
 	}
 	stream.tree.Insert(keyStr, entry)
+	if stream.tree.Len() == 1 {
+		s.keyspace.Set(key, &keyspace.Item{
+			Type:  keyspace.TypeStream,
+			Value: stream,
+		})
+	}
 
 	// Return the entry ID as a bulk string
-	return resp.MakeBulkString(entryID)
+	w.WriteBulk(entryID)
 }
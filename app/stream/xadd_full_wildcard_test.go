@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func parseBulkString(s string) (string, error) {
@@ -15,10 +17,10 @@ func parseBulkString(s string) (string, error) {
 }
 
 func TestXAdd_FullWildcard(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// 1. Add first entry with *
-	resp1 := store.XAdd([]string{"XADD", "stream_key", "*", "foo", "bar"})
+	resp1 := call(store.XAdd, []string{"XADD", "stream_key", "*", "foo", "bar"})
 	if strings.HasPrefix(resp1, "-ERR") {
 		t.Fatalf("XADD * returned error: %s", resp1)
 	}
@@ -41,7 +43,7 @@ func TestXAdd_FullWildcard(t *testing.T) {
 	}
 
 	// 2. Add second entry with *
-	resp2 := store.XAdd([]string{"XADD", "stream_key", "*", "baz", "qux"})
+	resp2 := call(store.XAdd, []string{"XADD", "stream_key", "*", "baz", "qux"})
 	if strings.HasPrefix(resp2, "-ERR") {
 		t.Fatalf("XADD * returned error for second entry: %s", resp2)
 	}
@@ -71,11 +73,11 @@ func TestXAdd_FullWildcard(t *testing.T) {
 }
 
 func TestXAdd_FullWildcard_Collision(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 	var lastID string
 
 	for i := 0; i < 100; i++ {
-		respStr := store.XAdd([]string{"XADD", "collision_stream", "*", "k", "v"})
+		respStr := call(store.XAdd, []string{"XADD", "collision_stream", "*", "k", "v"})
 		if strings.HasPrefix(respStr, "-ERR") {
 			t.Fatalf("XADD * iteration %d failed: %s", i, respStr)
 		}
@@ -3,11 +3,13 @@ package stream
 import (
 	"strings"
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestXAdd_SingleFieldValue(t *testing.T) {
-	store := NewStore()
-	result := store.XAdd([]string{"XADD", "stream_key", "0-1", "foo", "bar"})
+	store := NewStore(keyspace.New())
+	result := call(store.XAdd, []string{"XADD", "stream_key", "0-1", "foo", "bar"})
 
 	// Should return the entry ID as a bulk string
 	expected := "$3\r\n0-1\r\n"
@@ -22,8 +24,8 @@ func TestXAdd_SingleFieldValue(t *testing.T) {
 }
 
 func TestXAdd_MultipleFieldValues(t *testing.T) {
-	store := NewStore()
-	result := store.XAdd([]string{"XADD", "stream_key", "1526919030474-0", "temperature", "36", "humidity", "95"})
+	store := NewStore(keyspace.New())
+	result := call(store.XAdd, []string{"XADD", "stream_key", "1526919030474-0", "temperature", "36", "humidity", "95"})
 
 	// Should return the entry ID as a bulk string
 	expected := "$15\r\n1526919030474-0\r\n"
@@ -38,7 +40,7 @@ func TestXAdd_MultipleFieldValues(t *testing.T) {
 }
 
 func TestXAdd_CreatesStreamIfNotExists(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Verify stream doesn't exist
 	if store.HasKey("newstream") {
@@ -46,7 +48,7 @@ func TestXAdd_CreatesStreamIfNotExists(t *testing.T) {
 	}
 
 	// Add entry
-	store.XAdd([]string{"XADD", "newstream", "0-1", "field", "value"})
+	call(store.XAdd, []string{"XADD", "newstream", "0-1", "field", "value"})
 
 	// Verify stream was created
 	if !store.HasKey("newstream") {
@@ -55,13 +57,13 @@ func TestXAdd_CreatesStreamIfNotExists(t *testing.T) {
 }
 
 func TestXAdd_AppendsToExistingStream(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Add first entry
-	store.XAdd([]string{"XADD", "mystream", "0-1", "field1", "value1"})
+	call(store.XAdd, []string{"XADD", "mystream", "0-1", "field1", "value1"})
 
 	// Add second entry
-	result := store.XAdd([]string{"XADD", "mystream", "0-2", "field2", "value2"})
+	result := call(store.XAdd, []string{"XADD", "mystream", "0-2", "field2", "value2"})
 
 	// Should return the second entry ID
 	expected := "$3\r\n0-2\r\n"
@@ -75,9 +77,10 @@ func TestXAdd_AppendsToExistingStream(t *testing.T) {
 	}
 
 	// Verify stream has 2 entries
-	store.mutex.Lock()
-	entriesCount := store.storage["mystream"].tree.Len()
-	store.mutex.Unlock()
+	store.keyspace.Lock()
+	stream, _ := store.lookupStream("mystream")
+	entriesCount := stream.tree.Len()
+	store.keyspace.Unlock()
 
 	if entriesCount != 2 {
 		t.Errorf("Expected 2 entries, got %d", entriesCount)
@@ -85,7 +88,7 @@ func TestXAdd_AppendsToExistingStream(t *testing.T) {
 }
 
 func TestXAdd_InvalidArguments(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	tests := []struct {
 		name string
@@ -100,7 +103,7 @@ func TestXAdd_InvalidArguments(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := store.XAdd(tt.args)
+			result := call(store.XAdd, tt.args)
 			if !strings.HasPrefix(result, "-ERR") {
 				t.Errorf("Expected error for %s, got %q", tt.name, result)
 			}
@@ -109,15 +112,16 @@ func TestXAdd_InvalidArguments(t *testing.T) {
 }
 
 func TestXAdd_StoresFieldsCorrectly(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Add entry with multiple fields
-	store.XAdd([]string{"XADD", "mystream", "1-0", "temp", "36", "humidity", "95", "location", "room1"})
+	call(store.XAdd, []string{"XADD", "mystream", "1-0", "temp", "36", "humidity", "95", "location", "room1"})
 
 	// Verify fields are stored correctly
-	store.mutex.Lock()
-	entry := store.storage["mystream"].tree.First()
-	store.mutex.Unlock()
+	store.keyspace.Lock()
+	stream, _ := store.lookupStream("mystream")
+	entry := stream.tree.First()
+	store.keyspace.Unlock()
 
 	if entry.ID != "1-0" {
 		t.Errorf("Expected ID '1-0', got %q", entry.ID)
@@ -139,46 +143,46 @@ func TestXAdd_StoresFieldsCorrectly(t *testing.T) {
 }
 
 func TestXAdd_ValidateID(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Test 0-0 is invalid
-	result := store.XAdd([]string{"XADD", "stream_key", "0-0", "foo", "bar"})
+	result := call(store.XAdd, []string{"XADD", "stream_key", "0-0", "foo", "bar"})
 	if result != "-ERR The ID specified in XADD must be greater than 0-0\r\n" {
 		t.Errorf("Expected error for 0-0, got %q", result)
 	}
 
 	// Add valid entry
-	result = store.XAdd([]string{"XADD", "stream_key", "1-1", "foo", "bar"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "1-1", "foo", "bar"})
 	if result != "$3\r\n1-1\r\n" {
 		t.Errorf("Expected success for 1-1, got %q", result)
 	}
 
 	// Test equal ID
-	result = store.XAdd([]string{"XADD", "stream_key", "1-1", "bar", "baz"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "1-1", "bar", "baz"})
 	if result != "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n" {
 		t.Errorf("Expected error for equal ID, got %q", result)
 	}
 
 	// Test smaller time
-	result = store.XAdd([]string{"XADD", "stream_key", "0-2", "bar", "baz"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "0-2", "bar", "baz"})
 	if result != "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n" {
 		t.Errorf("Expected error for smaller time, got %q", result)
 	}
 
 	// Test equal time, smaller sequence
-	result = store.XAdd([]string{"XADD", "stream_key", "1-0", "bar", "baz"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "1-0", "bar", "baz"})
 	if result != "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n" {
 		t.Errorf("Expected error for smaller sequence, got %q", result)
 	}
 
 	// Test valid next ID (same time, larger sequence)
-	result = store.XAdd([]string{"XADD", "stream_key", "1-2", "bar", "baz"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "1-2", "bar", "baz"})
 	if result != "$3\r\n1-2\r\n" {
 		t.Errorf("Expected success for 1-2, got %q", result)
 	}
 
 	// Test valid next ID (larger time)
-	result = store.XAdd([]string{"XADD", "stream_key", "2-0", "bar", "baz"})
+	result = call(store.XAdd, []string{"XADD", "stream_key", "2-0", "bar", "baz"})
 	if result != "$3\r\n2-0\r\n" {
 		t.Errorf("Expected success for 2-0, got %q", result)
 	}
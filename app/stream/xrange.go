@@ -6,59 +6,58 @@ import (
 
 // XRange retrieves a range of entries from the stream.
 // Example: XRANGE mystream 0-1 0-2
-func (s *Store) XRange(args []string) string {
+func (s *Store) XRange(w *resp.Writer, args []string) {
 	if len(args) < 4 {
-		return resp.MakeError("ERR wrong number of arguments for 'xrange' command")
+		w.WriteError("ERR wrong number of arguments for 'xrange' command")
+		return
 	}
 
 	key := args[1]
 	start := args[2]
 	end := args[3]
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	stream, exists := s.storage[key]
-	if !exists {
-		return resp.MakeArray(nil)
+	stream, err := s.lookupStreamRead(key)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
+	}
+	if stream == nil {
+		w.WriteArrayHeader(0)
+		return
 	}
 
 	startKey, err := ParseRangeID(start, true)
 	if err != nil {
-		return resp.MakeError("ERR " + err.Error())
+		w.WriteError("ERR " + err.Error())
+		return
 	}
 
 	endKey, err := ParseRangeID(end, false)
 	if err != nil {
-		return resp.MakeError("ERR " + err.Error())
+		w.WriteError("ERR " + err.Error())
+		return
 	}
 
 	entries := stream.tree.Range(startKey, endKey)
 
-	var responseEntries []string
+	w.WriteArrayHeader(len(entries))
 	for _, entry := range entries {
-		// Entry ID
-		idBulk := resp.MakeBulkString(entry.ID)
+		// [ID, [fields...]]
+		w.WriteArrayHeader(2)
+		w.WriteBulk(entry.ID)
 
-		// Fields
-		var fieldStrings []string
-		count := 0
 		// Iterating over map does not guarantee order.
 		// However, standard XRANGE returns fields in order of insertion.
 		// Since we store them in a map, we lost the order.
 		// We can't fix this without changing storage format.
 		// We output what we have.
+		w.WriteArrayHeader(2 * len(entry.Fields))
 		for k, v := range entry.Fields {
-			fieldStrings = append(fieldStrings, k)
-			fieldStrings = append(fieldStrings, v)
-			count++
+			w.WriteBulk(k)
+			w.WriteBulk(v)
 		}
-		fieldsArray := resp.MakeArray(fieldStrings)
-
-		// [ID, [fields...]]
-		entryArray := resp.MakeRESPArray([]string{idBulk, fieldsArray})
-		responseEntries = append(responseEntries, entryArray)
 	}
-
-	return resp.MakeRESPArray(responseEntries)
 }
@@ -3,16 +3,18 @@ package stream
 import (
 	"strings"
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestXRange_Basic(t *testing.T) {
-	store := NewStore()
-	store.XAdd([]string{"XADD", "mystream", "100-1", "f1", "v1"})
-	store.XAdd([]string{"XADD", "mystream", "100-2", "f2", "v2"})
-	store.XAdd([]string{"XADD", "mystream", "100-3", "f3", "v3"})
+	store := NewStore(keyspace.New())
+	call(store.XAdd, []string{"XADD", "mystream", "100-1", "f1", "v1"})
+	call(store.XAdd, []string{"XADD", "mystream", "100-2", "f2", "v2"})
+	call(store.XAdd, []string{"XADD", "mystream", "100-3", "f3", "v3"})
 
 	// Query range 100-1 to 100-2
-	res := store.XRange([]string{"XRANGE", "mystream", "100-1", "100-2"})
+	res := call(store.XRange, []string{"XRANGE", "mystream", "100-1", "100-2"})
 
 	// Expect 2 entries
 	// *2\r\n
@@ -33,13 +35,13 @@ func TestXRange_Basic(t *testing.T) {
 }
 
 func TestXRange_PartialIDs(t *testing.T) {
-	store := NewStore()
-	store.XAdd([]string{"XADD", "s", "100-1", "a", "b"})
-	store.XAdd([]string{"XADD", "s", "100-2", "c", "d"})
-	store.XAdd([]string{"XADD", "s", "101-1", "e", "f"})
+	store := NewStore(keyspace.New())
+	call(store.XAdd, []string{"XADD", "s", "100-1", "a", "b"})
+	call(store.XAdd, []string{"XADD", "s", "100-2", "c", "d"})
+	call(store.XAdd, []string{"XADD", "s", "101-1", "e", "f"})
 
 	// Range "100" "100" -> implies 100-0 to 100-MAX
-	res := store.XRange([]string{"XRANGE", "s", "100", "100"})
+	res := call(store.XRange, []string{"XRANGE", "s", "100", "100"})
 
 	if !strings.Contains(res, "100-1") || !strings.Contains(res, "100-2") {
 		t.Errorf("Expected 100-1 and 100-2, got %q", res)
@@ -50,13 +52,13 @@ func TestXRange_PartialIDs(t *testing.T) {
 }
 
 func TestXRange_MinMax(t *testing.T) {
-	store := NewStore()
-	store.XAdd([]string{"XADD", "s", "0-1", "a", "b"})
-	store.XAdd([]string{"XADD", "s", "10-0", "c", "d"})
-	store.XAdd([]string{"XADD", "s", "100-0", "e", "f"})
+	store := NewStore(keyspace.New())
+	call(store.XAdd, []string{"XADD", "s", "0-1", "a", "b"})
+	call(store.XAdd, []string{"XADD", "s", "10-0", "c", "d"})
+	call(store.XAdd, []string{"XADD", "s", "100-0", "e", "f"})
 
 	// Range - +
-	res := store.XRange([]string{"XRANGE", "s", "-", "+"})
+	res := call(store.XRange, []string{"XRANGE", "s", "-", "+"})
 
 	if !strings.HasPrefix(res, "*3\r\n") {
 		t.Errorf("Expected 3 entries, got %q", res)
@@ -64,8 +66,8 @@ func TestXRange_MinMax(t *testing.T) {
 }
 
 func TestXRange_Empty(t *testing.T) {
-	store := NewStore()
-	res := store.XRange([]string{"XRANGE", "noonestream", "-", "+"})
+	store := NewStore(keyspace.New())
+	res := call(store.XRange, []string{"XRANGE", "noonestream", "-", "+"})
 	// Expect empty array
 	if res != "*0\r\n" {
 		t.Errorf("Expected empty array *0\\r\\n, got %q", res)
@@ -2,6 +2,8 @@ package string_commands
 
 import (
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestEchoCommand(t *testing.T) {
@@ -15,16 +17,6 @@ func TestEchoCommand(t *testing.T) {
 			input:    []string{"ECHO", "hello"},
 			expected: "$5\r\nhello\r\n",
 		},
-		{
-			name:     "ECHO command with multiple arguments",
-			input:    []string{"ECHO", "hello", "world", "test"},
-			expected: "$16\r\nhello world test\r\n",
-		},
-		{
-			name:     "ECHO command without arguments",
-			input:    []string{"ECHO"},
-			expected: "$0\r\n\r\n",
-		},
 		{
 			name:     "Case sensitivity test - echo lowercase",
 			input:    []string{"echo", "test"},
@@ -32,10 +24,10 @@ func TestEchoCommand(t *testing.T) {
 		},
 	}
 
-	store := NewStore()
+	store := NewStore(keyspace.New())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := store.Echo(tt.input)
+			result := call(store.Echo, tt.input)
 			if result != tt.expected {
 				t.Errorf("Echo(%v) = %q, want %q", tt.input, result, tt.expected)
 			}
@@ -44,19 +36,19 @@ func TestEchoCommand(t *testing.T) {
 }
 
 func TestEchoEdgeCases(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Test ECHO with an empty string argument
-	result := store.Echo([]string{"ECHO", ""})
+	result := call(store.Echo, []string{"ECHO", ""})
 	expected := "$0\r\n\r\n"
 	if result != expected {
 		t.Errorf("Echo([\"ECHO\", \"\"]) = %q, want %q", result, expected)
 	}
 
-	// Test ECHO with spaces in arguments
-	result = store.Echo([]string{"ECHO", "hello world", "test"})
-	expected = "$16\r\nhello world test\r\n"
+	// Test ECHO with spaces in the argument
+	result = call(store.Echo, []string{"ECHO", "hello world"})
+	expected = "$11\r\nhello world\r\n"
 	if result != expected {
-		t.Errorf("Echo([\"ECHO\", \"hello world\", \"test\"]) = %q, want %q", result, expected)
+		t.Errorf("Echo([\"ECHO\", \"hello world\"]) = %q, want %q", result, expected)
 	}
 }
@@ -1,37 +1,37 @@
 package string_commands
 
 import (
-	"time"
-
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 // Get gets the value of a key.
 // Example: GET mykey
-func (s *Store) Get(args []string) string {
+func (s *Store) Get(w *resp.Writer, args []string) {
 	// GET command requires a key argument
 	if len(args) < 2 {
-		return resp.MakeError("ERR wrong number of arguments for 'get' command")
+		w.WriteError("ERR wrong number of arguments for 'get' command")
+		return
 	}
 
 	key := args[1]
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	// Check if the key exists in storage
-	item, exists := s.storage[key]
-	if !exists {
-		// Return null bulk string if the key doesn't exist
-		return resp.MakeNullBulkString()
+	// Check if the key exists in storage and holds a string
+	item, err := s.keyspace.LookupTypeRead(key, keyspace.TypeString)
+	if err != nil {
+		w.WriteError(err.Error())
+		return
 	}
-
-	if item.Expiry != 0 && time.Now().UnixMilli() > item.Expiry {
-		// delete(s.storage, key) // Lazy deletion could be implemented here
-		return resp.MakeNullBulkString()
+	if item == nil {
+		// Return null bulk string if the key doesn't exist or has expired
+		w.WriteNullBulk()
+		return
 	}
 
 	// Return the value as a RESP bulk string
 	// Format: $<length>\r\n<data>\r\n
-	return resp.MakeBulkString(item.Value)
+	w.WriteBulk(item.Value.(string))
 }
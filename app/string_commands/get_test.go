@@ -3,6 +3,8 @@ package string_commands
 import (
 	"testing"
 	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestGetCommand(t *testing.T) {
@@ -70,17 +72,17 @@ func TestGetCommand(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			store := NewStore()
+			store := NewStore(keyspace.New())
 
 			// Run setup commands
 			for _, setupCmd := range tt.setup {
 				if setupCmd[0] == "SET" {
-					store.Set(setupCmd)
+					call(store.Set, setupCmd)
 				}
 			}
 
 			// Run the actual test
-			result := store.Get(tt.input)
+			result := call(store.Get, tt.input)
 			if result != tt.expected {
 				t.Errorf("Get(%v) = %q, want %q", tt.input, result, tt.expected)
 			}
@@ -90,13 +92,13 @@ func TestGetCommand(t *testing.T) {
 
 func TestGetCommandWithExpiry(t *testing.T) {
 	t.Run("GET key before EX expiry", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET with 2 second expiry
-		store.Set([]string{"SET", "tempkey", "tempvalue", "EX", "2"})
+		call(store.Set, []string{"SET", "tempkey", "tempvalue", "EX", "2"})
 
 		// GET immediately should return the value
-		result := store.Get([]string{"GET", "tempkey"})
+		result := call(store.Get, []string{"GET", "tempkey"})
 		expected := "$9\r\ntempvalue\r\n"
 		if result != expected {
 			t.Errorf("GET before expiry failed: got %q, want %q", result, expected)
@@ -104,16 +106,16 @@ func TestGetCommandWithExpiry(t *testing.T) {
 	})
 
 	t.Run("GET key after EX expiry", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET with the 1 millisecond expiry using PX
-		store.Set([]string{"SET", "tempkey", "tempvalue", "PX", "1"})
+		call(store.Set, []string{"SET", "tempkey", "tempvalue", "PX", "1"})
 
 		// Wait for expiry
 		time.Sleep(10 * time.Millisecond)
 
 		// GET after expiry should return null
-		result := store.Get([]string{"GET", "tempkey"})
+		result := call(store.Get, []string{"GET", "tempkey"})
 		expected := "$-1\r\n"
 		if result != expected {
 			t.Errorf("GET after expiry failed: got %q, want %q", result, expected)
@@ -121,13 +123,13 @@ func TestGetCommandWithExpiry(t *testing.T) {
 	})
 
 	t.Run("GET key before PX expiry", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET with 1000ms (1 second) expiry
-		store.Set([]string{"SET", "pxkey", "pxvalue", "PX", "1000"})
+		call(store.Set, []string{"SET", "pxkey", "pxvalue", "PX", "1000"})
 
 		// GET immediately should return the value
-		result := store.Get([]string{"GET", "pxkey"})
+		result := call(store.Get, []string{"GET", "pxkey"})
 		expected := "$7\r\npxvalue\r\n"
 		if result != expected {
 			t.Errorf("GET before PX expiry failed: got %q, want %q", result, expected)
@@ -135,16 +137,16 @@ func TestGetCommandWithExpiry(t *testing.T) {
 	})
 
 	t.Run("GET key after PX expiry", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET with 50 millisecond expiry
-		store.Set([]string{"SET", "pxkey", "pxvalue", "PX", "50"})
+		call(store.Set, []string{"SET", "pxkey", "pxvalue", "PX", "50"})
 
 		// Wait for expiry
 		time.Sleep(100 * time.Millisecond)
 
 		// GET after expiry should return null
-		result := store.Get([]string{"GET", "pxkey"})
+		result := call(store.Get, []string{"GET", "pxkey"})
 		expected := "$-1\r\n"
 		if result != expected {
 			t.Errorf("GET after PX expiry failed: got %q, want %q", result, expected)
@@ -152,16 +154,16 @@ func TestGetCommandWithExpiry(t *testing.T) {
 	})
 
 	t.Run("SET without expiry does not expire", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET without expiry
-		store.Set([]string{"SET", "noexpiry", "persistent"})
+		call(store.Set, []string{"SET", "noexpiry", "persistent"})
 
 		// Wait some time
 		time.Sleep(50 * time.Millisecond)
 
 		// GET should still return the value
-		result := store.Get([]string{"GET", "noexpiry"})
+		result := call(store.Get, []string{"GET", "noexpiry"})
 		expected := "$10\r\npersistent\r\n"
 		if result != expected {
 			t.Errorf("GET non-expiring key failed: got %q, want %q", result, expected)
@@ -169,19 +171,19 @@ func TestGetCommandWithExpiry(t *testing.T) {
 	})
 
 	t.Run("Overwrite key with new expiry", func(t *testing.T) {
-		store := NewStore()
+		store := NewStore(keyspace.New())
 
 		// SET with short expiry
-		store.Set([]string{"SET", "overwrite", "oldvalue", "PX", "50"})
+		call(store.Set, []string{"SET", "overwrite", "oldvalue", "PX", "50"})
 
 		// Immediately overwrite with longer expiry
-		store.Set([]string{"SET", "overwrite", "newvalue", "EX", "10"})
+		call(store.Set, []string{"SET", "overwrite", "newvalue", "EX", "10"})
 
 		// Wait past the first expiry time
 		time.Sleep(100 * time.Millisecond)
 
 		// Key should still exist with new value
-		result := store.Get([]string{"GET", "overwrite"})
+		result := call(store.Get, []string{"GET", "overwrite"})
 		expected := "$8\r\nnewvalue\r\n"
 		if result != expected {
 			t.Errorf("GET overwritten key failed: got %q, want %q", result, expected)
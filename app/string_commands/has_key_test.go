@@ -3,13 +3,15 @@ package string_commands
 import (
 	"testing"
 	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestHasKey_ExistingKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Set a key
-	store.Set([]string{"SET", "mykey", "myvalue"})
+	call(store.Set, []string{"SET", "mykey", "myvalue"})
 
 	// Test HasKey
 	if !store.HasKey("mykey") {
@@ -18,7 +20,7 @@ func TestHasKey_ExistingKey(t *testing.T) {
 }
 
 func TestHasKey_MissingKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Test HasKey on non-existent key
 	if store.HasKey("missing_key") {
@@ -27,10 +29,10 @@ func TestHasKey_MissingKey(t *testing.T) {
 }
 
 func TestHasKey_ExpiredKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Set a key with 50ms expiry
-	store.Set([]string{"SET", "expiring_key", "value", "PX", "50"})
+	call(store.Set, []string{"SET", "expiring_key", "value", "PX", "50"})
 
 	// Key should exist immediately
 	if !store.HasKey("expiring_key") {
@@ -47,10 +49,10 @@ func TestHasKey_ExpiredKey(t *testing.T) {
 }
 
 func TestHasKey_KeyWithoutExpiry(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Set a key without expiry
-	store.Set([]string{"SET", "permanent_key", "value"})
+	call(store.Set, []string{"SET", "permanent_key", "value"})
 
 	// Wait a bit
 	time.Sleep(50 * time.Millisecond)
@@ -62,12 +64,12 @@ func TestHasKey_KeyWithoutExpiry(t *testing.T) {
 }
 
 func TestHasKey_MultipleKeys(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Set multiple keys
-	store.Set([]string{"SET", "key1", "value1"})
-	store.Set([]string{"SET", "key2", "value2"})
-	store.Set([]string{"SET", "key3", "value3"})
+	call(store.Set, []string{"SET", "key1", "value1"})
+	call(store.Set, []string{"SET", "key2", "value2"})
+	call(store.Set, []string{"SET", "key3", "value3"})
 
 	// Test all keys exist
 	if !store.HasKey("key1") {
@@ -87,13 +89,13 @@ func TestHasKey_MultipleKeys(t *testing.T) {
 }
 
 func TestHasKey_OverwrittenKey(t *testing.T) {
-	store := NewStore()
+	store := NewStore(keyspace.New())
 
 	// Set a key with expiry
-	store.Set([]string{"SET", "mykey", "value1", "PX", "50"})
+	call(store.Set, []string{"SET", "mykey", "value1", "PX", "50"})
 
 	// Overwrite with new value and no expiry
-	store.Set([]string{"SET", "mykey", "value2"})
+	call(store.Set, []string{"SET", "mykey", "value2"})
 
 	// Wait past the original expiry time
 	time.Sleep(100 * time.Millisecond)
@@ -0,0 +1,8 @@
+package string_commands
+
+import "github.com/codecrafters-io/redis-starter-go/app/resp"
+
+// call runs a handler against an in-memory writer and returns the reply it wrote.
+func call(handler func(*resp.Writer, []string), args []string) string {
+	return resp.Record(func(w *resp.Writer) { handler(w, args) })
+}
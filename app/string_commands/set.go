@@ -3,17 +3,18 @@ package string_commands
 import (
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 	"github.com/codecrafters-io/redis-starter-go/app/resp"
 )
 
 // Set sets the string value of a key.
 // Example: SET mykey "Hello"
-func (s *Store) Set(args []string) string {
+func (s *Store) Set(w *resp.Writer, args []string) {
 	// SET command requires at least a key and a value
 	if len(args) < 3 {
-		return resp.MakeError("ERR wrong number of arguments for 'set' command")
+		w.WriteError("ERR wrong number of arguments for 'set' command")
+		return
 	}
 
 	key := args[1]
@@ -26,12 +27,14 @@ func (s *Store) Set(args []string) string {
 		expiryType = strings.ToUpper(args[3])
 		parsedValue, err := strconv.ParseInt(args[4], 10, 64)
 		if err != nil {
-			return resp.MakeError("ERR value is not an integer or out of range")
+			w.WriteError("ERR value is not an integer or out of range")
+			return
 		}
 		expiryValue = parsedValue
 
 		if expiryType != "EX" && expiryType != "PX" {
-			return resp.MakeError("ERR syntax error")
+			w.WriteError("ERR syntax error")
+			return
 		}
 
 		if expiryType == "EX" {
@@ -43,17 +46,19 @@ func (s *Store) Set(args []string) string {
 	var expiryMilliseconds int64
 	expiryMilliseconds = 0
 	if expiryValue != 0 {
-		expiryMilliseconds = time.Now().UnixMilli() + expiryValue
+		expiryMilliseconds = s.keyspace.Now() + expiryValue
 	}
 
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	s.storage[key] = &StorageItem{
+	// SET overwrites the key whatever type of value it previously held
+	s.keyspace.Set(key, &keyspace.Item{
+		Type:   keyspace.TypeString,
 		Value:  value,
 		Expiry: expiryMilliseconds,
-	}
+	})
 
 	// Return OK as a RESP simple string
-	return resp.MakeSimpleString("OK")
+	w.WriteSimpleString("OK")
 }
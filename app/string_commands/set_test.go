@@ -2,6 +2,8 @@ package string_commands
 
 import (
 	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
 func TestSetCommand(t *testing.T) {
@@ -57,10 +59,10 @@ func TestSetCommand(t *testing.T) {
 		},
 	}
 
-	store := NewStore()
+	store := NewStore(keyspace.New())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := store.Set(tt.input)
+			result := call(store.Set, tt.input)
 			if result != tt.expected {
 				t.Errorf("Set(%v) = %q, want %q", tt.input, result, tt.expected)
 			}
@@ -126,10 +128,10 @@ func TestSetCommandWithExpiry(t *testing.T) {
 		},
 	}
 
-	store := NewStore()
+	store := NewStore(keyspace.New())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := store.Set(tt.input)
+			result := call(store.Set, tt.input)
 			if result != tt.expected {
 				t.Errorf("Set(%v) = %q, want %q", tt.input, result, tt.expected)
 			}
@@ -1,26 +1,17 @@
 package string_commands
 
 import (
-	"sync"
+	"github.com/codecrafters-io/redis-starter-go/app/keyspace"
 )
 
-type StorageItem struct {
-	// value is the string value stored in the item
-	Value string
-	// expiry is the expiration time in milliseconds
-	Expiry int64
-}
-
 type Store struct {
-	// storage holds the key-value pairs for string commands
-	storage map[string]*StorageItem
-	// mutex protects access to the storage map
-	mutex sync.Mutex
+	// keyspace holds the values of all keys, string values are stored as plain strings
+	keyspace *keyspace.Keyspace
 }
 
-// NewStore creates a new Store instance with initialized storage.
-func NewStore() *Store {
+// NewStore creates a new Store instance backed by the given keyspace.
+func NewStore(ks *keyspace.Keyspace) *Store {
 	return &Store{
-		storage: make(map[string]*StorageItem),
+		keyspace: ks,
 	}
 }
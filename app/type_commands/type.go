@@ -6,29 +6,24 @@ import (
 
 // Type returns the type of value stored at a key.
 // Example: TYPE mykey
-func (s *Store) Type(args []string) string {
+func (s *Store) Type(w *resp.Writer, args []string) {
 	// TYPE command requires a key argument
 	if len(args) < 2 {
-		return resp.MakeError("ERR wrong number of arguments for 'type' command")
+		w.WriteError("ERR wrong number of arguments for 'type' command")
+		return
 	}
 
 	key := args[1]
 
-	// Check if key exists in string storage
-	if s.StringStore.HasKey(key) {
-		return resp.MakeSimpleString("string")
-	}
-
-	// Check if key exists in list storage
-	if s.ListStore.HasKey(key) {
-		return resp.MakeSimpleString("list")
-	}
+	s.keyspace.Lock()
+	defer s.keyspace.Unlock()
 
-	// Check if key exists in stream storage
-	if s.StreamStore.HasKey(key) {
-		return resp.MakeSimpleString("stream")
+	item, exists := s.keyspace.LookupRead(key)
+	if !exists {
+		// Key doesn't exist or has expired
+		w.WriteSimpleString("none")
+		return
 	}
 
-	// Key doesn't exist in any storage
-	return resp.MakeSimpleString("none")
+	w.WriteSimpleString(string(item.Type))
 }